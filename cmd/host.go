@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/will-rowe/archer/pkg/host"
+)
+
+// command line options
+var (
+	hostReference *string // the host reference genome (FASTA) to index
+	hostOut       *string // where to write the host index
+	hostKmerSize  *int    // k-mer size for the host index
+	hostScaled    *uint64 // scale factor for sampling host k-mers
+)
+
+// hostCmd represents the host command
+var hostCmd = &cobra.Command{
+	Use:   "host",
+	Short: "Build a host k-mer index for host read depletion",
+	Long: `Build a host k-mer index for host read depletion.
+
+	This command will build a k-mer index from a host reference
+	genome (e.g. human), which can be loaded by the Archer service
+	to remove host reads before upload (archer launch --hostIndex).
+
+	Roughly 1 in every --scaled k-mers is kept in the index, which
+	sets the index size. For the human genome, the default of 20
+	gives an index of around 1GB, which is held in memory by the
+	service. Larger values use less memory but sample fewer k-mers
+	from each read.
+
+	Example usage:
+
+	archer host --reference GRCh38.fa.gz --out human.idx
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		buildHostIndex()
+	},
+}
+
+func init() {
+	hostReference = hostCmd.Flags().String("reference", "", "the host reference genome to index (FASTA, can be gzipped)")
+	hostOut = hostCmd.Flags().String("out", "host.idx", "where to write the host index")
+	hostKmerSize = hostCmd.Flags().Int("kmerSize", host.DefaultKmerSize, "k-mer size for the host index")
+	hostScaled = hostCmd.Flags().Uint64("scaled", host.DefaultScaled, "keep roughly 1 in every scaled k-mers")
+	hostCmd.MarkFlagRequired("reference")
+	rootCmd.AddCommand(hostCmd)
+}
+
+// buildHostIndex will build and write a host index
+func buildHostIndex() {
+
+	// open the reference
+	fh, err := os.Open(*hostReference)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer fh.Close()
+	var reader io.Reader = bufio.NewReader(fh)
+	if strings.HasSuffix(*hostReference, ".gz") {
+		gr, err := gzip.NewReader(reader)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer gr.Close()
+		reader = gr
+	}
+
+	// build the index
+	log.Printf("building host index from %v (k=%d, scaled=%d)", *hostReference, *hostKmerSize, *hostScaled)
+	idx, err := host.Build(reader, *hostKmerSize, *hostScaled)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// write the index
+	out, err := os.Create(*hostOut)
+	if err != nil {
+		log.Fatal(err)
+	}
+	bw := bufio.NewWriter(out)
+	if err := idx.Write(bw); err != nil {
+		log.Fatal(err)
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %d k-mers to %v", idx.GetNumHashes(), *hostOut)
+}
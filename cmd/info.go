@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// command line options
+var (
+	grpcAddrInfo  *string   // the address of the gRPC server
+	grpcPortInfo  *string   // TCP port to listen to by the gRPC server
+	infoIDs       *[]string // sample IDs to query
+	infoStates    *[]string // sample states to query
+	infoScheme    *string   // amplicon scheme to query
+	infoStartedAt *string   // only return samples started at or after this time
+	infoStartedBy *string   // only return samples started before this time
+	infoEndedAt   *string   // only return samples finished at or after this time
+	infoEndedBy   *string   // only return samples finished before this time
+	infoPageSize  *int32    // number of samples per response page
+	infoLimit     *int32    // maximum number of samples to return
+	infoCursor    *string   // resume a query after this sample ID
+)
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Query the sample records held by an Archer service",
+	Long: `Query the sample records held by an Archer service.
+
+	This command will request sample records from the Archer
+	database and print each one as a line of JSON. Records can
+	be filtered by sample ID, state, scheme and time. Times
+	should be given in RFC3339 format.
+
+	Example usage:
+
+	archer info --state SUCCESS --state ERROR --startedAfter 2021-03-01T00:00:00Z
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		info()
+	},
+}
+
+func init() {
+	grpcAddrInfo = infoCmd.Flags().String("grpcAddress", DefaultServerAddress, "address of the server hosting the Archer service")
+	grpcPortInfo = infoCmd.Flags().String("grpcPort", DefaultgRPCport, "TCP port to listen to by the gRPC server")
+	infoIDs = infoCmd.Flags().StringSlice("id", nil, "sample ID to query (can be repeated)")
+	infoStates = infoCmd.Flags().StringSlice("state", nil, "sample state to query (can be repeated)")
+	infoScheme = infoCmd.Flags().String("scheme", "", "only return samples processed against this scheme")
+	infoStartedAt = infoCmd.Flags().String("startedAfter", "", "only return samples started at or after this time")
+	infoStartedBy = infoCmd.Flags().String("startedBefore", "", "only return samples started before this time")
+	infoEndedAt = infoCmd.Flags().String("endedAfter", "", "only return samples finished at or after this time")
+	infoEndedBy = infoCmd.Flags().String("endedBefore", "", "only return samples finished before this time")
+	infoPageSize = infoCmd.Flags().Int32("pageSize", 0, "number of samples per response (0 == server default)")
+	infoLimit = infoCmd.Flags().Int32("limit", 0, "maximum number of samples to return (0 == all)")
+	infoCursor = infoCmd.Flags().String("cursor", "", "resume a query after this sample ID")
+	rootCmd.AddCommand(infoCmd)
+}
+
+// info sets up and runs a gRPC Archer client for querying sample records
+func info() {
+
+	// build the request
+	req := &api.GetInfoRequest{
+		ApiVersion: DefaultAPIVersion,
+		SampleIDs:  *infoIDs,
+		Scheme:     *infoScheme,
+		PageSize:   *infoPageSize,
+		Limit:      *infoLimit,
+		Cursor:     *infoCursor,
+	}
+	for _, state := range *infoStates {
+		val, ok := api.State_value[strings.ToUpper(state)]
+		if !ok {
+			log.Fatalf("unknown sample state: %v", state)
+		}
+		req.States = append(req.States, api.State(val))
+	}
+	var err error
+	if req.StartedAfter, err = parseTimeFlag(*infoStartedAt); err != nil {
+		log.Fatal(err)
+	}
+	if req.StartedBefore, err = parseTimeFlag(*infoStartedBy); err != nil {
+		log.Fatal(err)
+	}
+	if req.EndedAfter, err = parseTimeFlag(*infoEndedAt); err != nil {
+		log.Fatal(err)
+	}
+	if req.EndedBefore, err = parseTimeFlag(*infoEndedBy); err != nil {
+		log.Fatal(err)
+	}
+
+	// connect to the gRPC server
+	addr := fmt.Sprintf("%s:%s", *grpcAddrInfo, *grpcPortInfo)
+	log.Printf("dialing %v", addr)
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("could not connect to the Archer gRPC server: %v", err)
+	}
+	defer conn.Close()
+
+	// establish the client and open the stream
+	client := api.NewArcherClient(conn)
+	stream, err := client.GetInfo(context.Background(), req)
+	if err != nil {
+		log.Fatalf("could not open info stream: %v", err)
+	}
+
+	// print the samples as they arrive
+	nextCursor := ""
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errStatus, _ := status.FromError(err)
+			log.Fatal(errStatus.Message())
+		}
+		for _, sample := range resp.GetSamples() {
+			data, err := protojson.Marshal(sample)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(string(data))
+		}
+		nextCursor = resp.GetNextCursor()
+	}
+	if len(nextCursor) != 0 {
+		log.Printf("more samples may be available, resume the query using --cursor %v", nextCursor)
+	}
+}
+
+// parseTimeFlag is a helper function to convert
+// an optional RFC3339 time to a proto timestamp.
+func parseTimeFlag(flag string) (*timestamp.Timestamp, error) {
+	if len(flag) == 0 {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, flag)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse time (%v): %v", flag, err)
+	}
+	return ptypes.TimestampProto(t)
+}
@@ -5,25 +5,64 @@ import (
 	"fmt"
 	"log"
 	"runtime"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/will-rowe/archer/pkg/amplicons"
 	"github.com/will-rowe/archer/pkg/bucket"
+	"github.com/will-rowe/archer/pkg/host"
 	"github.com/will-rowe/archer/pkg/protocol/grpc"
 	"github.com/will-rowe/archer/pkg/service/v1"
 )
 
 // command line options
 var (
-	grpcAddr      *string // the address of the gRPC server
-	grpcPort      *string // TCP port to listen to by the gRPC server
-	dbPath        *string // dbPath sets the location and filename for the Archer database
-	manifestURL   *string // manifestURL tells archer where to collect the ARTIC primer scheme manifest
-	numWorkers    *int    // number of concurrent request handlers to use
-	numProcessors *int    // number of processors to use
-	awsBucketName *string // the AWS S3 bucket name for uploading data to
-	awsRegion     *string // the AWS region to use
-	logFile       *string // the log file
+	grpcAddr         *string        // the address of the gRPC server
+	grpcPort         *string        // TCP port to listen to by the gRPC server
+	dbPath           *string        // dbPath sets the location and filename for the Archer database
+	manifestURL      *string        // manifestURL tells archer where to collect the ARTIC primer scheme manifest
+	schemesRoot      *string        // a local copy of the primer-schemes repository
+	schemeCache      *string        // where to cache downloaded primer schemes
+	kmerSize         *int           // the default k-mer size for sketching
+	sketchSize       *int           // the default sketch size
+	canonical        *bool          // hash canonical k-mers by default
+	lengthTolerance  *float64       // the default fraction above/below the mean amplicon size to keep reads within
+	jaccardThreshold *float64       // the default minimum Jaccard score to keep a read
+	containThreshold *float64       // the default minimum containment score to keep a read
+	minMeanQuality   *float64       // the default minimum mean base quality to keep a read
+	trimWindow       *int           // the default sliding window size for quality trimming
+	trimQuality      *float64       // the default minimum mean window quality for quality trimming
+	primerTrim       *string        // the default primer trimming for kept reads
+	maxAmpliconDepth *int           // the default maximum number of reads to keep for each amplicon
+	hostIndex        *string        // a host k-mer index for removing host reads
+	hostThreshold    *float64       // the fraction of sampled k-mers in the host index to remove a read
+	multiHitRatio    *float64       // the default fraction of the best amplicon score for a second hit to flag a read
+	multiHitAction   *string        // the default multi-hit reads to discard
+	qcMinDepth       *int           // the default number of kept reads an amplicon needs to pass QC
+	qcWarnCoverage   *float64       // the estimated reference coverage below which a sample is given a WARN
+	qcFailCoverage   *float64       // the estimated reference coverage below which a sample is given a FAIL
+	headerPolicy     *string        // the default read header policy for kept reads
+	headerMapDir     *string        // where to keep the local files mapping scrubbed headers to the originals
+	gateMinReads     *int           // the minimum number of kept reads for a sample to pass the QC gate
+	gateMinAmplicons *int           // the minimum number of amplicons at the QC depth for a sample to pass the QC gate
+	gateMaxOffTarget *float64       // the maximum off-target fraction for a sample to pass the QC gate
+	gateAction       *string        // what happens to samples that fail the QC gate
+	stagingDir       *string        // where to stage reads while the QC gate is checked
+	numWorkers       *int           // number of concurrent request handlers to use
+	maxRetries       *int           // number of times to retry a sample after a transient upload error
+	progressInterval *time.Duration // time between progress updates sent to watchers
+	numProcessors    *int           // number of processors to use
+	awsBucketName    *string        // the AWS S3 bucket name for uploading data to
+	awsRegion        *string        // the AWS region to use
+	storageURL       *string        // where to upload data to (overrides awsBucketName)
+	awsProfile       *string        // the AWS shared config profile to use
+	s3Endpoint       *string        // a custom S3-compatible endpoint
+	s3PathStyle      *bool          // use path-style addressing for S3
+	s3SkipVerify     *bool          // skip TLS certificate verification for S3
+	logFile          *string        // the log file
+	recoveryPolicy   *string        // how to handle samples left unfinished by the last service run
+	retryErrors      *bool          // re-queue errored samples when the service launches
 )
 
 // launchCmd represents the launch command
@@ -35,7 +74,8 @@ var launchCmd = &cobra.Command{
 	This will start a gRPC server running that will
 	accept incoming Process and Watch requests. It
 	will offer the Archer API for filtering, compressing
-	and uploading ARTIC reads to an S3 endpoint.`,
+	and uploading ARTIC reads to an S3 endpoint or a
+	local directory (see --storage).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		launchArcher()
 	},
@@ -45,12 +85,48 @@ func init() {
 	grpcAddr = launchCmd.Flags().String("grpcAddress", DefaultServerAddress, "address to announce on")
 	grpcPort = launchCmd.Flags().String("grpcPort", DefaultgRPCport, "TCP port to listen to by the gRPC server")
 	dbPath = launchCmd.Flags().String("dbPath", DefaultDbPath, "location to store the Archer database")
-	manifestURL = launchCmd.Flags().String("manifestURL", DefaultManifestURL, "the ARTIC primer scheme manifest url")
+	manifestURL = launchCmd.Flags().String("manifestURL", DefaultManifestURL, "the ARTIC primer scheme manifest url or path")
+	schemesRoot = launchCmd.Flags().String("schemesRoot", "", "a local copy of the primer-schemes repository to use instead of downloading schemes (for offline use)")
+	schemeCache = launchCmd.Flags().String("schemeCache", "", "where to cache downloaded primer schemes (default is in the dbPath)")
+	kmerSize = launchCmd.Flags().Int("kmerSize", amplicons.DefaultKmerSize, "the default k-mer size for sketching reads and amplicons (can be set per request)")
+	sketchSize = launchCmd.Flags().Int("sketchSize", amplicons.DefaultSketchSize, "the default sketch size for reads and amplicons (can be set per request)")
+	canonical = launchCmd.Flags().Bool("canonical", amplicons.DefaultCanonical, "hash canonical k-mers by default (can be set per request)")
+	lengthTolerance = launchCmd.Flags().Float64("lengthTolerance", service.DefaultLengthTolerance, "the default fraction above/below the mean amplicon size to keep reads within (can be set per request)")
+	jaccardThreshold = launchCmd.Flags().Float64("jaccardThreshold", service.DefaultJaccardThreshold, "the default minimum Jaccard score to keep a read (can be set per request)")
+	containThreshold = launchCmd.Flags().Float64("containmentThreshold", service.DefaultContainmentThreshold, "the default minimum containment score to keep a read (can be set per request)")
+	minMeanQuality = launchCmd.Flags().Float64("minMeanQuality", service.DefaultMinMeanQuality, "the default minimum mean base quality (Phred) to keep a read, 0 keeps all reads (can be set per request)")
+	trimWindow = launchCmd.Flags().Int("trimWindow", 0, "the default sliding window size for trimming the low quality end of reads, 0 turns off trimming (can be set per request)")
+	trimQuality = launchCmd.Flags().Float64("trimQuality", 0, "the default minimum mean window quality (Phred) for trimming reads, 0 turns off trimming (can be set per request)")
+	primerTrim = launchCmd.Flags().String("primerTrim", "none", "the default primer trimming for kept reads (none, soft or hard), where soft trimming masks primers and hard trimming removes them (can be set per request)")
+	maxAmpliconDepth = launchCmd.Flags().Int("maxAmpliconDepth", 0, "the default maximum number of reads to keep for each amplicon, where the first reads are kept and 0 keeps all reads (can be set per request)")
+	hostIndex = launchCmd.Flags().String("hostIndex", "", "a host k-mer index (built with archer host) used to remove host reads before upload")
+	hostThreshold = launchCmd.Flags().Float64("hostThreshold", host.DefaultThreshold, "the fraction of a read's sampled k-mers found in the host index to remove it")
+	multiHitRatio = launchCmd.Flags().Float64("multiHitRatio", service.DefaultMultiHitRatio, "the default fraction of a read's best amplicon score that a second amplicon must reach to flag the read as ambiguous or chimeric (can be set per request)")
+	multiHitAction = launchCmd.Flags().String("multiHitAction", "keep", "the default flagged reads to discard (keep, chimeric or all), where keep only counts them and all discards ambiguous and chimeric reads (can be set per request)")
+	qcMinDepth = launchCmd.Flags().Int("qcMinDepth", service.DefaultQCMinDepth, "the default number of kept reads an amplicon needs to pass QC (can be set per request)")
+	qcWarnCoverage = launchCmd.Flags().Float64("qcWarnCoverage", service.DefaultQCWarnCoverage, "the estimated reference coverage (percentage) from passing amplicons below which a sample is given a QC WARN")
+	qcFailCoverage = launchCmd.Flags().Float64("qcFailCoverage", service.DefaultQCFailCoverage, "the estimated reference coverage (percentage) from passing amplicons below which a sample is given a QC FAIL")
+	headerPolicy = launchCmd.Flags().String("headerPolicy", "keep", "the default read header policy for kept reads (keep, index or sample), where index and sample replace the original headers (can be set per request)")
+	headerMapDir = launchCmd.Flags().String("headerMapDir", "", "a local directory to keep files mapping scrubbed read headers to the originals (not uploaded)")
+	gateMinReads = launchCmd.Flags().Int("gateMinReads", 0, "the minimum number of kept reads for a sample to pass the QC gate, 0 isn't checked")
+	gateMinAmplicons = launchCmd.Flags().Int("gateMinAmplicons", 0, "the minimum number of amplicons at the QC depth (--qcMinDepth) for a sample to pass the QC gate, 0 isn't checked")
+	gateMaxOffTarget = launchCmd.Flags().Float64("gateMaxOffTarget", 0, "the maximum fraction of reads not matching an amplicon (or removed as host) for a sample to pass the QC gate, 0 isn't checked")
+	gateAction = launchCmd.Flags().String("gateAction", "block", "what happens to samples that fail the QC gate (block or quarantine), where block doesn't upload the reads and quarantine uploads them with a quarantine/ prefix")
+	stagingDir = launchCmd.Flags().String("stagingDir", "", "where to stage reads while the QC gate is checked (default is in the dbPath)")
 	numWorkers = launchCmd.Flags().Int("numWorkers", 2, "number of concurrent request handlers to use")
+	maxRetries = launchCmd.Flags().Int("maxRetries", service.DefaultMaxRetries, "number of times to retry a sample after a transient upload error")
+	progressInterval = launchCmd.Flags().Duration("progressInterval", service.DefaultProgressInterval, "time between progress updates sent to watchers for each running sample")
 	numProcessors = launchCmd.Flags().IntP("numProcessors", "p", -1, "number of processors to use (-1 == all)")
 	awsBucketName = launchCmd.Flags().String("awsBucketName", DefaultBucketName, "the AWS S3 bucket name for data upload")
 	awsRegion = launchCmd.Flags().String("awsRegion", bucket.DefaultRegion, "the AWS region to use")
+	storageURL = launchCmd.Flags().String("storage", "", "where to upload data to, as s3://bucket/prefix or file:///path/to/dir (overrides --awsBucketName)")
+	awsProfile = launchCmd.Flags().String("awsProfile", "", "the AWS shared config profile to get credentials from (default uses the AWS credential chain)")
+	s3Endpoint = launchCmd.Flags().String("s3Endpoint", "", "a custom S3-compatible endpoint URL (e.g. https://minio.local:9000)")
+	s3PathStyle = launchCmd.Flags().Bool("s3PathStyle", false, "use path-style S3 addressing (needed by most S3-compatible endpoints)")
+	s3SkipVerify = launchCmd.Flags().Bool("s3SkipVerify", false, "skip TLS certificate verification for the S3 endpoint (testing only)")
 	logFile = launchCmd.Flags().StringP("logFile", "l", "", "where to write the server log (if unset, STDERR used)")
+	recoveryPolicy = launchCmd.Flags().String("recoveryPolicy", "resume", "how to handle samples left queued or running by the last run (resume, restart or error)")
+	retryErrors = launchCmd.Flags().Bool("retryErrors", false, "re-queue any errored samples when the service launches")
 	rootCmd.AddCommand(launchCmd)
 }
 
@@ -61,8 +137,44 @@ func launchArcher() {
 	// get top level context
 	ctx := context.Background()
 
+	// get the storage location
+	if len(*storageURL) == 0 {
+		*storageURL = fmt.Sprintf("s3://%s", *awsBucketName)
+	}
+
 	// get the service API
-	serverAPI, cleanupAPI, err := service.NewArcher(service.SetNumWorkers(*numWorkers), service.SetDb(*dbPath), service.SetManifest(*manifestURL), service.SetBucket(*awsBucketName, *awsRegion))
+	serverAPI, cleanupAPI, err := service.NewArcher(
+		service.SetNumWorkers(*numWorkers),
+		service.SetMaxRetries(*maxRetries),
+		service.SetProgressInterval(*progressInterval),
+		service.SetDb(*dbPath),
+		service.SetManifest(*manifestURL),
+		service.SetSchemesRoot(*schemesRoot),
+		service.SetSchemeCache(*schemeCache),
+		service.SetSketchParams(*kmerSize, *sketchSize, *canonical),
+		service.SetLengthTolerance(*lengthTolerance),
+		service.SetScoreThresholds(*jaccardThreshold, *containThreshold),
+		service.SetMinMeanQuality(*minMeanQuality),
+		service.SetQualityTrim(*trimWindow, *trimQuality),
+		service.SetPrimerTrim(*primerTrim),
+		service.SetMaxAmpliconDepth(*maxAmpliconDepth),
+		service.SetHostIndex(*hostIndex, *hostThreshold),
+		service.SetMultiHits(*multiHitRatio, *multiHitAction),
+		service.SetSampleQC(*qcMinDepth, *qcWarnCoverage, *qcFailCoverage),
+		service.SetHeaderPolicy(*headerPolicy),
+		service.SetHeaderMapDir(*headerMapDir),
+		service.SetQCGate(*gateMinReads, *gateMinAmplicons, *gateMaxOffTarget, *gateAction),
+		service.SetStagingDir(*stagingDir),
+		service.SetStorage(*storageURL,
+			bucket.SetRegion(*awsRegion),
+			bucket.SetProfile(*awsProfile),
+			bucket.SetEndpoint(*s3Endpoint),
+			bucket.SetPathStyle(*s3PathStyle),
+			bucket.SetSkipVerify(*s3SkipVerify),
+		),
+		service.SetRecoveryPolicy(*recoveryPolicy),
+		service.SetRetryErrors(*retryErrors),
+	)
 	if err != nil {
 		log.Fatalf("could not create Archer service: %v", err)
 	}
@@ -2,14 +2,15 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 
 	api "github.com/will-rowe/archer/pkg/api/v1"
 )
@@ -44,9 +45,121 @@ var processCmd = &cobra.Command{
 		"schemeVersion": 3
 	}
 
+	The inputFASTQfiles can be FASTQ files (plain, gzip or bgzip),
+	directories of FASTQ files (e.g. a MinKNOW fastq_pass/barcode05/
+	folder) or glob patterns (e.g. "/path/to/run/*.fastq.gz"). These
+	are expanded to the FASTQ files found when the request is accepted.
+
 	For scheme and schemeVersion, these must be available in the
 	manifest provided to the server (archer launch --manifestURL ...).
 	By default, the server uses the ARTIC primer scheme manifest.
+
+	Reads are kept if their length is within 20% of the mean amplicon
+	size (or the server --lengthTolerance). The read filters can be set
+	for each request, using absolute or relative length bounds and a
+	minimum mean base quality (Phred):
+
+	{
+		...
+		"minLength": 300,
+		"maxLength": 700,
+		"lengthTolerance": 0.3,
+		"minMeanQuality": 9
+	}
+
+	Reads can also be trimmed before filtering, by cutting each read
+	at the first sliding window with a mean quality below trimQuality
+	(reads aren't trimmed by default, so set both unless the server
+	has defaults for them):
+
+	{
+		...
+		"trimWindow": 20,
+		"trimQuality": 10
+	}
+
+	Primers can be trimmed from the kept reads before upload, using
+	the primers of the amplicon each read was assigned to. SOFT_TRIM
+	masks primer bases (lower case with a quality of 0) and HARD_TRIM
+	removes them (the server --primerTrim is used by default):
+
+	{
+		...
+		"primerTrim": "HARD_TRIM"
+	}
+
+	The number of reads kept for each amplicon can be capped, which
+	keeps the first reads assigned to each amplicon (the ARTIC
+	pipeline normalises to 200x). The server --maxAmpliconDepth is
+	used by default:
+
+	{
+		...
+		"maxAmpliconDepth": 200
+	}
+
+	The raw and normalised amplicon coverage are both recorded.
+
+	The original read headers (which include run, flowcell and device
+	details) can be replaced before upload, with either the read index
+	(INDEX_HEADERS) or the sample ID and read index (SAMPLE_HEADERS).
+	The server --headerPolicy is used by default and any mapping to the
+	original headers is only kept on the server (--headerMapDir):
+
+	{
+		...
+		"headerPolicy": "SAMPLE_HEADERS"
+	}
+
+	Once a sample is processed, amplicons with fewer kept reads than
+	qcMinDepth are reported as dropouts in its sampleQC. The passing
+	amplicons are used to estimate the reference coverage, which gives
+	a PASS, WARN or FAIL verdict (the server --qcMinDepth is used by
+	default, see also --qcWarnCoverage and --qcFailCoverage):
+
+	{
+		...
+		"qcMinDepth": 10
+	}
+
+	The filters used for a sample are recorded in its processStats,
+	along with the number of reads trimmed, the number dropped by
+	each filter and histograms of the mean read quality for all reads
+	and for kept reads.
+
+	Reads are scored against amplicons using the Jaccard similarity
+	by default. Short or truncated reads can be scored by how much
+	of the read is contained in an amplicon instead, and the score
+	threshold can also be set (0 uses the default for the scoring):
+
+	{
+		...
+		"scoring": "CONTAINMENT",
+		"scoreThreshold": 0.8
+	}
+
+	Reads whose second best amplicon score is at least multiHitRatio
+	times their best score are counted as ambiguous (the amplicons
+	overlap) or chimeric (they don't). They are kept by default, but
+	DISCARD_CHIMERIC or DISCARD_MULTI_HIT will drop them (the server
+	--multiHitRatio and --multiHitAction are used by default):
+
+	{
+		...
+		"multiHitRatio": 0.8,
+		"multiHitAction": "DISCARD_CHIMERIC"
+	}
+
+	The k-mer size, sketch size and k-mer strand used for sketching
+	can also be set, overriding the server settings (archer launch
+	--kmerSize ...). This can help for schemes with longer amplicons:
+
+	{
+		...
+		"kmerSize": 15,
+		"sketchSize": 100,
+		"kmerStrand": "CANONICAL"
+	}
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
 		process()
@@ -73,8 +186,12 @@ func process() {
 	}
 
 	// collect request
-	var processRequest *api.ProcessRequest
-	if err := json.NewDecoder(os.Stdin).Decode(&processRequest); err != nil {
+	data, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+	processRequest := &api.ProcessRequest{}
+	if err := protojson.Unmarshal(data, processRequest); err != nil {
 		log.Fatal(err)
 	}
 
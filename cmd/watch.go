@@ -7,6 +7,8 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -20,6 +22,7 @@ import (
 var (
 	grpcAddrWatch *string // the address of the gRPC server
 	grpcPortWatch *string // TCP port to listen to by the gRPC server
+	watchProgress *bool   // also print progress updates for running samples
 )
 
 // watchCmd represents the watch command
@@ -29,9 +32,10 @@ var watchCmd = &cobra.Command{
 	Long: `Watch a running Archer service.
 	
 	This command will start a gRPC message stream and 
-	print samples that have completed processing. It 
-	will include sample name, amplicon coverage, S3
-	location and processing time.`,
+	print samples as they are queued, start, make progress
+	and finish processing. For finished samples it will
+	include sample name, amplicon coverage, S3 location
+	and processing time.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		watcher()
 	},
@@ -40,6 +44,7 @@ var watchCmd = &cobra.Command{
 func init() {
 	grpcAddrWatch = watchCmd.Flags().String("grpcAddress", DefaultServerAddress, "address of the server hosting the Archer service")
 	grpcPortWatch = watchCmd.Flags().String("grpcPort", DefaultgRPCport, "TCP port to listen to by the gRPC server")
+	watchProgress = watchCmd.Flags().Bool("progress", false, "also print progress updates for running samples")
 	rootCmd.AddCommand(watchCmd)
 }
 
@@ -67,7 +72,7 @@ func watcher() {
 
 	// create a watch stream request
 	log.Println("opening watch stream")
-	req := &api.WatchRequest{ApiVersion: DefaultAPIVersion, SendFinished: true}
+	req := &api.WatchRequest{ApiVersion: DefaultAPIVersion, SendFinished: true, SendProgress: *watchProgress}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	stream, err := client.Watch(ctx, req)
@@ -76,7 +81,6 @@ func watcher() {
 	}
 
 	// wait for samples to be sent
-	log.Print("completed samples:")
 	go func() {
 		for {
 			resp, err := stream.Recv()
@@ -93,9 +97,25 @@ func watcher() {
 			}
 
 			// log stream
-			for _, sample := range resp.GetSamples() {
-				covAmps, totAmps, meanCov := service.GetAmpliconCoverage(sample.GetProcessStats())
-				log.Printf("\t- %v\t(%d/%d reads kept, %d/%d amplicons covered (mean coverage = %.0f))\t%v\tprocessed in %d seconds", sample.GetSampleID(), sample.GetProcessStats().GetKeptReads(), sample.GetProcessStats().GetTotalReads(), covAmps, totAmps, meanCov, sample.GetEndpoint(), (sample.GetEndTime().Seconds - sample.GetStartTime().GetSeconds()))
+			switch resp.GetEventType() {
+			case api.EventType_SNAPSHOT:
+				log.Printf("current samples:")
+				for _, sample := range resp.GetSamples() {
+					printSample(sample)
+				}
+			case api.EventType_QUEUED:
+				for _, sample := range resp.GetSamples() {
+					log.Printf("\t- %v\tqueued", sample.GetSampleID())
+				}
+			case api.EventType_STATE_CHANGE:
+				for _, sample := range resp.GetSamples() {
+					printSample(sample)
+				}
+			case api.EventType_PROGRESS:
+				progress := resp.GetProgress()
+				for _, sample := range resp.GetSamples() {
+					log.Printf("\t- %v\t(%d/%d reads kept, processing file %d, %d bytes uploaded)", sample.GetSampleID(), progress.GetKeptReads(), progress.GetTotalReads(), progress.GetCurrentFile()+1, progress.GetBytesUploaded())
+				}
 			}
 		}
 	}()
@@ -119,3 +139,53 @@ func watcher() {
 	}
 	log.Printf("finished")
 }
+
+// printSample logs the state of a sample, including
+// the processing summary for processed samples.
+func printSample(sample *api.SampleInfo) {
+	if sample.GetState() != api.State_SUCCESS && sample.GetState() != api.State_FAILED_QC {
+		log.Printf("\t- %v\t%v", sample.GetSampleID(), sample.GetState())
+		return
+	}
+	covAmps, totAmps, meanCov := service.GetAmpliconCoverage(sample.GetProcessStats())
+	log.Printf("\t- %v\t(%d/%d reads kept, %d/%d amplicons covered (mean coverage = %.0f))\t%v\tprocessed in %d seconds", sample.GetSampleID(), sample.GetProcessStats().GetKeptReads(), sample.GetProcessStats().GetTotalReads(), covAmps, totAmps, meanCov, sample.GetEndpoint(), (sample.GetEndTime().Seconds - sample.GetStartTime().GetSeconds()))
+
+	// report the QC verdict and any amplicon dropouts
+	if qc := sample.GetSampleQC(); qc != nil {
+		log.Printf("\t\t- QC %v\t(%d/%d amplicons at %dx or more, %.1f%% of reference covered)", qc.GetVerdict(), qc.GetPassedAmplicons(), qc.GetTotalAmplicons(), qc.GetMinDepth(), qc.GetReferenceCoverage())
+		if len(qc.GetDroppedAmplicons()) != 0 {
+			log.Printf("\t\t- dropped amplicons: %v", strings.Join(qc.GetDroppedAmplicons(), ", "))
+		}
+		if len(qc.GetGateFailures()) != 0 {
+			log.Printf("\t\t- failed QC gate (quarantined = %v): %v", qc.GetQuarantined(), strings.Join(qc.GetGateFailures(), "; "))
+		}
+	}
+
+	// report the coverage of each reference for multi-reference schemes
+	references := sample.GetProcessStats().GetReferenceStats()
+	if len(references) > 1 {
+		names := make([]string, 0, len(references))
+		for name := range references {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			rs := references[name]
+			log.Printf("\t\t- reference %v\t(%d/%d amplicons covered, %.1f%% of reference covered)", name, rs.GetCoveredAmplicons(), rs.GetAmplicons(), rs.GetReferenceCoverage())
+		}
+	}
+
+	// report the pool balance for multi-pool schemes
+	pools := sample.GetProcessStats().GetPoolStats()
+	if len(pools) > 1 {
+		names := make([]string, 0, len(pools))
+		for name := range pools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ps := pools[name]
+			log.Printf("\t\t- pool %v\t(%d/%d amplicons covered (mean coverage = %.0f, %.0f%% of best pool))", name, ps.GetCoveredAmplicons(), ps.GetAmplicons(), ps.GetMeanCoverage(), 100*ps.GetRelativeCoverage())
+		}
+	}
+}
@@ -4,9 +4,10 @@ package amplicons
 import (
 	"bytes"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -20,140 +21,524 @@ import (
 )
 
 const (
-	kmerSize   = 7
-	sketchSize = 24
-	canonical  = true
+
+	// DefaultKmerSize is the default k-mer size used for sketching
+	DefaultKmerSize = 7
+
+	// DefaultSketchSize is the default number of minimums kept in a sketch
+	DefaultSketchSize = 24
+
+	// DefaultCanonical is the default for hashing canonical k-mers
+	DefaultCanonical = true
+
+	// MaxKmerSize is the largest k-mer size allowed
+	MaxKmerSize = 32
 )
 
+// SketchParams sets how amplicons and
+// reads are sketched.
+type SketchParams struct {
+	KmerSize   int  // k-mer size
+	SketchSize int  // number of minimums kept in a sketch
+	Canonical  bool // hash canonical k-mers (so strand doesn't matter)
+}
+
+// DefaultSketchParams returns the default SketchParams,
+// which suit the SARS-CoV-2 schemes.
+func DefaultSketchParams() SketchParams {
+	return SketchParams{
+		KmerSize:   DefaultKmerSize,
+		SketchSize: DefaultSketchSize,
+		Canonical:  DefaultCanonical,
+	}
+}
+
+// Check will check the SketchParams are usable.
+func (sp SketchParams) Check() error {
+	if sp.KmerSize < 1 || sp.KmerSize > MaxKmerSize {
+		return fmt.Errorf("k-mer size must be between 1 and %d", MaxKmerSize)
+	}
+	if sp.SketchSize < 1 {
+		return errors.New("sketch size must be greater than 0")
+	}
+	return nil
+}
+
+// String returns a short description of the
+// SketchParams, which is unique for each set
+// of values.
+func (sp SketchParams) String() string {
+	strand := "canonical"
+	if !sp.Canonical {
+		strand = "forward"
+	}
+	return fmt.Sprintf("k%d-s%d-%s", sp.KmerSize, sp.SketchSize, strand)
+}
+
 // Amplicon stores the minimal information needed
 // by Archer to perform FASTQ filtering for
 // amplicon enrichment.
 type Amplicon struct {
-	refName  string           // reference sequence ID
-	start    int              // start of leftmost primer (0-based indexing)
-	end      int              // end of rightmost primer
-	sequence []byte           // reference sequence for amplicon (incl. primer sequences)
-	sketch   *minhash.MinHash // minhash sketch for the amplicon
+	refName      string           // reference sequence ID
+	start        int              // start of leftmost primer (0-based indexing)
+	end          int              // end of rightmost primer
+	pool         string           // primer pool for the amplicon (BED column 5)
+	sequence     []byte           // reference sequence for amplicon (incl. primer sequences)
+	sketch       *minhash.MinHash // minhash sketch for the amplicon
+	leftPrimers  []*primer        // left primers for the amplicon (incl. alts)
+	rightPrimers []*primer        // right primers for the amplicon (incl. alts)
 }
 
 // AmpliconSet is a collection amplicons
-// produced by a primer scheme.
-type AmpliconSet map[string]*Amplicon
+// produced by a primer scheme, plus an
+// index of the amplicon sketches.
+type AmpliconSet struct {
+	amplicons    map[string]*Amplicon // amplicons keyed by name
+	refLengths   map[string]int       // length of each reference sequence used by the amplicons
+	index        *sketchIndex         // inverted index of amplicon sketches
+	sketchParams SketchParams         // how the amplicons and reads are sketched
+}
+
+// newAmpliconSet returns an empty AmpliconSet.
+func newAmpliconSet() *AmpliconSet {
+	return &AmpliconSet{
+		amplicons:    make(map[string]*Amplicon),
+		refLengths:   make(map[string]int),
+		sketchParams: DefaultSketchParams(),
+	}
+}
+
+// GetSketchParams returns the parameters used
+// to sketch the amplicons and reads.
+func (as *AmpliconSet) GetSketchParams() SketchParams {
+	return as.sketchParams
+}
+
+// GetNumAmplicons returns the number of
+// amplicons in the set.
+func (as *AmpliconSet) GetNumAmplicons() int {
+	return len(as.amplicons)
+}
+
+// GetAmpliconNames returns the sorted names
+// of the amplicons in the set.
+func (as *AmpliconSet) GetAmpliconNames() []string {
+	names := make([]string, 0, len(as.amplicons))
+	for name := range as.amplicons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetPool returns the primer pool for an
+// amplicon, or an empty string if the amplicon
+// is not in the set.
+func (as *AmpliconSet) GetPool(amplicon string) string {
+	if a, ok := as.amplicons[amplicon]; ok {
+		return a.pool
+	}
+	return ""
+}
+
+// GetPoolNames returns the sorted names of
+// the primer pools used by the amplicons.
+func (as *AmpliconSet) GetPoolNames() []string {
+	pools := make(map[string]struct{})
+	for _, amplicon := range as.amplicons {
+		pools[amplicon.pool] = struct{}{}
+	}
+	names := make([]string, 0, len(pools))
+	for pool := range pools {
+		names = append(names, pool)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetReferenceLength returns the total length of
+// the reference sequences used by the amplicons.
+func (as *AmpliconSet) GetReferenceLength() int {
+	refLength := 0
+	for _, l := range as.refLengths {
+		refLength += l
+	}
+	return refLength
+}
+
+// GetSequenceLength returns the length of a
+// reference sequence used by the amplicons, or 0
+// if the reference is not used.
+func (as *AmpliconSet) GetSequenceLength(refName string) int {
+	return as.refLengths[refName]
+}
+
+// GetReferenceCoverage returns the fraction of the
+// reference covered by a set of amplicons, using
+// the amplicon coordinates (incl. primers) so that
+// overlapping amplicons are only counted once.
+// Unknown amplicon names are ignored.
+func (as *AmpliconSet) GetReferenceCoverage(ampliconNames []string) float64 {
+	refLength := as.GetReferenceLength()
+	if refLength == 0 {
+		return 0.0
+	}
+	covered := 0
+	for _, bases := range as.getCoveredBases(ampliconNames) {
+		covered += bases
+	}
+	return float64(covered) / float64(refLength)
+}
+
+// GetCoverageByReference returns the fraction of each
+// reference sequence covered by a set of amplicons
+// (see GetReferenceCoverage), keyed by reference.
+func (as *AmpliconSet) GetCoverageByReference(ampliconNames []string) map[string]float64 {
+	covered := as.getCoveredBases(ampliconNames)
+	coverage := make(map[string]float64, len(as.refLengths))
+	for refName, refLength := range as.refLengths {
+		coverage[refName] = 0.0
+		if refLength != 0 {
+			coverage[refName] = float64(covered[refName]) / float64(refLength)
+		}
+	}
+	return coverage
+}
+
+// GetReferenceNames returns the sorted names of
+// the reference sequences used by the amplicons.
+func (as *AmpliconSet) GetReferenceNames() []string {
+	refNames := make(map[string]struct{})
+	for _, amplicon := range as.amplicons {
+		refNames[amplicon.refName] = struct{}{}
+	}
+	names := make([]string, 0, len(refNames))
+	for refName := range refNames {
+		names = append(names, refName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetReference returns the reference sequence ID
+// for an amplicon, or an empty string if the
+// amplicon is not in the set.
+func (as *AmpliconSet) GetReference(amplicon string) string {
+	if a, ok := as.amplicons[amplicon]; ok {
+		return a.refName
+	}
+	return ""
+}
+
+// getCoveredBases returns the number of bases of each
+// reference covered by a set of amplicons, merging any
+// overlapping amplicons.
+func (as *AmpliconSet) getCoveredBases(ampliconNames []string) map[string]int {
+
+	// group the amplicon regions by reference, sorted by start
+	regions := make(map[string][][2]int)
+	for _, name := range ampliconNames {
+		amplicon, ok := as.amplicons[name]
+		if !ok {
+			continue
+		}
+		regions[amplicon.refName] = append(regions[amplicon.refName], [2]int{amplicon.start, amplicon.end})
+	}
+
+	// merge the overlapping regions for each reference and sum the covered bases
+	covered := make(map[string]int, len(regions))
+	for refName, refRegions := range regions {
+		sort.Slice(refRegions, func(i, j int) bool { return refRegions[i][0] < refRegions[j][0] })
+		start, end := refRegions[0][0], refRegions[0][1]
+		for _, region := range refRegions[1:] {
+			if region[0] > end {
+				covered[refName] += end - start
+				start, end = region[0], region[1]
+				continue
+			}
+			if region[1] > end {
+				end = region[1]
+			}
+		}
+		covered[refName] += end - start
+	}
+	return covered
+}
 
 // GetMeanSize returns the mean amplicon size.
 // Primers and inserts are included.
-func (as AmpliconSet) GetMeanSize() int {
+func (as *AmpliconSet) GetMeanSize() int {
 	meanSize := 0
-	for _, amplicon := range as {
+	for _, amplicon := range as.amplicons {
 		meanSize += (amplicon.end - amplicon.start)
 	}
-	meanSize /= len(as)
+	meanSize /= len(as.amplicons)
 	return meanSize
 }
 
-// GetTopHit will compare a read against a each amplicon
+// GetTopHit will compare a read against the amplicons
 // in the set and return the name of the amplicon with
-// the best match, plus the Jaccard distance and any
-// error.
-func (as AmpliconSet) GetTopHit(read []byte) (string, float64, error) {
+// the best match, plus the score and any error.
+//
+// The score is either the Jaccard similarity of the
+// read and amplicon sketches, or the containment of
+// the read in the amplicon, which doesn't penalise
+// reads that only cover part of an amplicon.
+//
+// Only amplicons sharing a sketch value with the read
+// are compared, using the sketch index. Ties go to the
+// amplicon name that sorts first.
+func (as *AmpliconSet) GetTopHit(read []byte, scoring api.Scoring) (string, float64, error) {
+	hits, err := as.GetHits(read, scoring, 1)
+	if err != nil || len(hits) == 0 {
+		return "", 0.0, err
+	}
+	return hits[0].Amplicon, hits[0].Score, nil
+}
+
+// GetHits will compare a read against the amplicons in
+// the set and return up to n amplicons that match the
+// read, ranked by score (see GetTopHit). All matching
+// amplicons are returned if n < 1.
+func (as *AmpliconSet) GetHits(read []byte, scoring api.Scoring, n int) ([]Hit, error) {
+
+	if _, ok := api.Scoring_name[int32(scoring)]; !ok {
+		return nil, fmt.Errorf("unsupported scoring: %v", scoring)
+	}
 
 	// sketch the query read
-	sketcher := minhash.New(kmerSize, sketchSize)
-	hasher, err := nthash.NewHasher(&read, kmerSize)
+	sketcher, err := as.sketchParams.sketch(read)
 	if err != nil {
-		return "", 0.0, err
+		return nil, err
+	}
+
+	// use the index if it's been built
+	var hits []Hit
+	if as.index == nil {
+		hits, err = as.getHitsScan(sketcher, scoring)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		hits = as.index.query(sketcher.GetSketch(), scoring)
+	}
+	if n > 0 && len(hits) > n {
+		hits = hits[:n]
 	}
-	sketcher.Add(hasher.Hash(canonical))
+	return hits, nil
+}
 
-	// compare the query against each amplicon
-	// TODO: this is inefficient and we could/should use an index
-	topDist := 0.0
-	topHit := ""
-	for ampliconName, amplicon := range as {
-		dist, err := sketcher.GetDistance(amplicon.sketch)
+// getHitsScan will compare a sketched read against each
+// amplicon in the set, without using the index.
+func (as *AmpliconSet) getHitsScan(sketcher *minhash.MinHash, scoring api.Scoring) ([]Hit, error) {
+	hits := []Hit{}
+	for ampliconName, amplicon := range as.amplicons {
+		var score float64
+		var err error
+		switch scoring {
+		case api.Scoring_JACCARD:
+			score, err = sketcher.GetDistance(amplicon.sketch)
+		case api.Scoring_CONTAINMENT:
+			score, err = sketcher.GetContainment(amplicon.sketch)
+		default:
+			err = fmt.Errorf("unsupported scoring: %v", scoring)
+		}
 		if err != nil {
-			return "", 0.0, err
+			return nil, err
 		}
-		if dist > topDist {
-			topDist = dist
-			topHit = ampliconName
+		if score > 0 {
+			hits = append(hits, Hit{Amplicon: ampliconName, Score: score})
 		}
 	}
-	return topHit, topDist, nil
+	rankHits(hits)
+	return hits, nil
 }
 
-// NewAmpliconSet downloads the primer set and
+// sketch returns a minhash sketch for a sequence.
+func (sp SketchParams) sketch(seq []byte) (*minhash.MinHash, error) {
+
+	// create a minhash sketcher
+	sketcher := minhash.New(sp.KmerSize, sp.SketchSize)
+
+	// create the ntHash iterator using a pointer to the sequence and a k-mer size
+	hasher, err := nthash.NewHasher(&seq, uint(sp.KmerSize))
+
+	// check for errors (e.g. bad k-mer size choice)
+	if err != nil {
+		return nil, err
+	}
+
+	// attach the hasher to the sketcher and populate the sketch
+	sketcher.Add(hasher.Hash(sp.Canonical))
+	return sketcher, nil
+}
+
+// Option is a wrapper struct used to pass functional
+// options to the manifest and AmpliconSet constructors.
+type Option func(opts *options) error
+
+// options holds the settings used when loading
+// the manifest and primer schemes.
+type options struct {
+	localRoot    string       // local copy of the primer-schemes repository
+	cacheDir     string       // directory to cache downloaded files in
+	sketchParams SketchParams // how the amplicons and reads are sketched
+}
+
+// SetLocalRoot is an option setter for GetManifest and
+// NewAmpliconSet that sets a local copy of the primer-schemes
+// repository to load files from, instead of downloading them.
+// Relative paths and GitHub URLs are resolved against it.
+func SetLocalRoot(root string) Option {
+	return func(x *options) error {
+		x.localRoot = root
+		return nil
+	}
+}
+
+// SetCacheDir is an option setter for GetManifest and
+// NewAmpliconSet that sets a directory to cache downloaded
+// files in, so that they can be reused across restarts.
+func SetCacheDir(cacheDir string) Option {
+	return func(x *options) error {
+		x.cacheDir = cacheDir
+		return nil
+	}
+}
+
+// SetSketchParams is an option setter for NewAmpliconSet
+// that sets the k-mer size, sketch size and canonical
+// mode used to sketch the amplicons and reads.
+func SetSketchParams(sketchParams SketchParams) Option {
+	return func(x *options) error {
+		if err := sketchParams.Check(); err != nil {
+			return err
+		}
+		x.sketchParams = sketchParams
+		return nil
+	}
+}
+
+// getOptions applies the options to the defaults.
+func getOptions(opts []Option) (*options, error) {
+	o := &options{
+		sketchParams: DefaultSketchParams(),
+	}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// NewAmpliconSet collects the primer set and
 // reference sequence for a primer scheme in
 // the manifest and returns an AmpliconSet.
-func NewAmpliconSet(manifest *api.Manifest, requestedScheme string, requestedVersion int32) (*AmpliconSet, error) {
-	a := make(AmpliconSet)
+//
+// Files are checked against any SHA256 checksums
+// in the manifest, and downloaded files are cached
+// if a cache directory is set.
+func NewAmpliconSet(manifest *api.Manifest, requestedScheme string, requestedVersion int32, opts ...Option) (*AmpliconSet, error) {
+	o, err := getOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	a := newAmpliconSet()
+	a.sketchParams = o.sketchParams
+	schemeMetadata, ok := manifest.GetSchemes()[requestedScheme]
+	if !ok {
+		return nil, fmt.Errorf("scheme not found in manifest: %v", requestedScheme)
+	}
+	version := strconv.Itoa(int(requestedVersion))
 
-	// download primers and create amplicons
-	primersURL := manifest.Schemes[requestedScheme].PrimerUrls[strconv.Itoa(int(requestedVersion))]
-	log.Tracef("downloading and parsing primers from: %v", primersURL)
-	if err := getPrimers(a, primersURL); err != nil {
+	// get primers and create amplicons
+	primersURL, ok := schemeMetadata.GetPrimerUrls()[version]
+	if !ok {
+		return nil, fmt.Errorf("no primers in manifest for %v, version %v", requestedScheme, version)
+	}
+	primersURL = resolveLocation(primersURL, o.localRoot)
+	log.Tracef("collecting and parsing primers from: %v", primersURL)
+	primers, err := getResource(primersURL, schemeMetadata.GetPrimerSha256Checksums()[version], getCachePath(o.cacheDir, requestedScheme, version, cachedPrimers))
+	if err != nil {
+		return nil, err
+	}
+	if err := readPrimers(a, bytes.NewReader(primers)); err != nil {
 		return nil, err
 	}
 
-	// download reference sequence and add seqs to amplicons
-	refURL := manifest.Schemes[requestedScheme].ReferenceUrls[strconv.Itoa(int(requestedVersion))]
-	log.Tracef("downloading reference sequence and extracting amplicons from: %v", refURL)
-	if err := getSequence(a, refURL); err != nil {
+	// get reference sequence and add seqs to amplicons
+	refURL, ok := schemeMetadata.GetReferenceUrls()[version]
+	if !ok {
+		return nil, fmt.Errorf("no reference in manifest for %v, version %v", requestedScheme, version)
+	}
+	refURL = resolveLocation(refURL, o.localRoot)
+	log.Tracef("collecting reference sequence and extracting amplicons from: %v", refURL)
+	reference, err := getResource(refURL, schemeMetadata.GetReferenceSha256Checksums()[version], getCachePath(o.cacheDir, requestedScheme, version, cachedReference))
+	if err != nil {
+		return nil, err
+	}
+	if err := readSequence(a, bytes.NewReader(reference)); err != nil {
 		return nil, err
 	}
 
 	// create amplicon sketches
-	log.Tracef("creating sketches for %d amplicons", len(a))
+	log.Tracef("creating sketches (%v) for %d amplicons", a.sketchParams, len(a.amplicons))
 	var wg sync.WaitGroup
-	wg.Add(len(a))
-	for _, amplicon := range a {
-		go func(wg *sync.WaitGroup, amplicon *Amplicon) {
+	errChan := make(chan error, len(a.amplicons))
+	wg.Add(len(a.amplicons))
+	for name, amplicon := range a.amplicons {
+		go func(wg *sync.WaitGroup, name string, amplicon *Amplicon) {
 			defer wg.Done()
-			amplicon.getSketch()
-		}(&wg, amplicon)
+			if err := amplicon.getSketch(a.sketchParams); err != nil {
+				errChan <- fmt.Errorf("could not sketch amplicon %v: %w", name, err)
+			}
+		}(&wg, name, amplicon)
 	}
 	wg.Wait()
+	close(errChan)
+	if err := <-errChan; err != nil {
+		return nil, err
+	}
 
-	return &a, nil
+	// index the sketches
+	log.Tracef("indexing sketches for %d amplicons", len(a.amplicons))
+	a.index = newSketchIndex(a.amplicons)
+	return a, nil
 }
 
 // getSketch will generate a minhash sketch for an amplicon.
-func (a *Amplicon) getSketch() error {
-
-	// create a minhash sketcher
-	a.sketch = minhash.New(kmerSize, sketchSize)
-
-	// create the ntHash iterator using a pointer to the sequence and a k-mer size
-	hasher, err := nthash.NewHasher(&a.sequence, kmerSize)
-
-	// check for errors (e.g. bad k-mer size choice)
+// It returns an error if the amplicon can't be sketched (e.g.
+// it is shorter than the k-mer size).
+func (a *Amplicon) getSketch(sketchParams SketchParams) error {
+	sketch, err := sketchParams.sketch(a.sequence)
 	if err != nil {
 		return err
 	}
-
-	// attach the hasher to the sketcher and populate the sketch
-	a.sketch.Add(hasher.Hash(canonical))
-
+	a.sketch = sketch
 	return nil
 }
 
-// getPrimers collects primers from a URL and constructs amplicons.
-// It populates the provided map and returns any error.
-func getPrimers(as AmpliconSet, url string) error {
-
-	// download the primer set
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+// readPrimers reads primers in BED format and constructs
+// amplicons. It adds them to the provided AmpliconSet and
+// returns any error. Each primer is kept, along with its
+// sequence if the BED has a 7th column.
+//
+// Amplicons are keyed by reference and name, so schemes
+// spanning several references (e.g. segmented genomes)
+// can reuse amplicon numbers. Amplicon names are only
+// qualified with the reference (ref:N) if the BED has
+// more than one reference.
+func readPrimers(as *AmpliconSet, primers io.Reader) error {
 
 	// set up a tsv reader
-	reader := csv.NewReader(resp.Body)
+	reader := csv.NewReader(primers)
 	reader.Comma = '\t'
 	reader.FieldsPerRecord = -1
 
 	// create amplicons from primers using ARTIC pipeline logic
+	amplicons := make(map[ampliconKey]*Amplicon)
+	refNames := make(map[string]struct{})
 	for {
 
 		// get the primer line and check errs/end of file
@@ -165,63 +550,100 @@ func getPrimers(as AmpliconSet, url string) error {
 			return err
 		}
 
-		// get amplicon name
-		ampliconName := strings.Split(line[3], "_")[1]
+		// get amplicon name and primer coordinates
+		if len(line) < 6 {
+			return fmt.Errorf("primer BED needs at least 6 columns: %v", line)
+		}
+		nameParts := strings.Split(line[3], "_")
+		if len(nameParts) < 3 || (nameParts[2] != "LEFT" && nameParts[2] != "RIGHT") {
+			return fmt.Errorf("primer name needs to be in the scheme_amplicon_side format (side is LEFT or RIGHT): %v", line[3])
+		}
+		ampliconName := nameParts[1]
+		start, err := strconv.Atoi(line[1])
+		if err != nil {
+			return err
+		}
+		end, err := strconv.Atoi(line[2])
+		if err != nil {
+			return err
+		}
+		p := &primer{
+			name:  line[3],
+			start: start,
+			end:   end,
+		}
+
+		// use the primer sequence if the BED has it, otherwise it is taken from the reference
+		if len(line) > 6 {
+			p.sequence = bytes.ToUpper([]byte(line[6]))
+		}
 
 		// update or create amplicon in set
-		amplicon, ok := as[ampliconName]
+		key := ampliconKey{line[0], ampliconName}
+		amplicon, ok := amplicons[key]
 		if !ok {
 			amplicon = &Amplicon{
 				refName:  line[0],
+				pool:     line[4],
 				start:    99999999,
 				end:      0,
 				sequence: nil,
 				sketch:   nil,
 			}
-			as[ampliconName] = amplicon
+			amplicons[key] = amplicon
+			refNames[line[0]] = struct{}{}
 		}
 
 		// detect primer orientation and update amplicon boundaries (accounts for alts)
 		switch line[5] {
 		case "+":
-			start, err := strconv.Atoi(line[1])
-			if err != nil {
-				return err
-			}
 			if start < amplicon.start {
 				amplicon.start = start
 			}
+			amplicon.leftPrimers = append(amplicon.leftPrimers, p)
 		case "-":
-			end, err := strconv.Atoi(line[2])
-			if err != nil {
-				return err
-			}
 			if end > amplicon.end {
 				amplicon.end = end
 			}
+			p.reverse = true
+			amplicon.rightPrimers = append(amplicon.rightPrimers, p)
 		default:
 			return fmt.Errorf("can't detect primer orientation from in %v", line)
 		}
 	}
+
+	// add the amplicons to the set
+	for key, amplicon := range amplicons {
+		name := key.name
+		if len(refNames) > 1 {
+			name = key.String()
+		}
+		as.amplicons[name] = amplicon
+	}
 	return nil
 }
 
-// getSequence collects reference sequence from a URL
-// updates amplicons in the provided map to include
-// their sequence. It returns any errror.
-// It populates the provided map and returns any error.
-func getSequence(as AmpliconSet, url string) error {
+// ampliconKey identifies an amplicon in a
+// primer BED by reference and name.
+type ampliconKey struct {
+	refName string
+	name    string
+}
 
-	// download the reference sequence file
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+// String returns the qualified amplicon
+// name (ref:N).
+func (ak ampliconKey) String() string {
+	return ak.refName + ":" + ak.name
+}
+
+// readSequence reads a reference sequence in FASTA format
+// and updates the amplicons in the provided AmpliconSet to
+// include their sequence. It returns any error.
+func readSequence(as *AmpliconSet, reference io.Reader) error {
 
 	// split into two readers
 	var fastaBuf bytes.Buffer
-	tee := io.TeeReader(resp.Body, &fastaBuf)
+	tee := io.TeeReader(reference, &fastaBuf)
 
 	// index the fasta
 	idx := bytes.Buffer{}
@@ -236,12 +658,34 @@ func getSequence(as AmpliconSet, url string) error {
 	}
 
 	// loop over the amplicons and populate the sequence fields
-	for _, amplicon := range as {
+	for _, amplicon := range as.amplicons {
+		if _, ok := as.refLengths[amplicon.refName]; !ok {
+			refLength, err := fa.Len(amplicon.refName)
+			if err != nil {
+				return err
+			}
+			as.refLengths[amplicon.refName] = int(refLength)
+		}
 		seq, err := fa.Get(amplicon.refName, uint64(amplicon.start), uint64(amplicon.end))
 		if err != nil {
 			return err
 		}
 		amplicon.sequence = []byte(seq)
+
+		// add any missing primer sequences
+		for _, p := range append(append([]*primer{}, amplicon.leftPrimers...), amplicon.rightPrimers...) {
+			if len(p.sequence) != 0 {
+				continue
+			}
+			seq, err := fa.Get(amplicon.refName, uint64(p.start), uint64(p.end))
+			if err != nil {
+				return err
+			}
+			p.sequence = bytes.ToUpper([]byte(seq))
+			if p.reverse {
+				p.sequence = revComp(p.sequence)
+			}
+		}
 	}
 	return nil
 }
@@ -1,63 +1,93 @@
 package amplicons
 
 import (
+	"bytes"
+	"path/filepath"
 	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
 )
 
 var (
-	manifestURL       = "https://raw.githubusercontent.com/artic-network/primer-schemes/master/schemes_manifest.json"
-	primersURL        = "https://github.com/artic-network/primer-schemes/raw/master/nCoV-2019/V3/nCoV-2019.primer.bed"
-	refURL            = "https://github.com/artic-network/primer-schemes/raw/master/nCoV-2019/V3/nCoV-2019.reference.fasta"
-	numSCOV2amplicons = 98
-	queryRead         = []byte("CAAACCAACCAACTTTCGATCTCTTGTAGATCTGTTCTCTAAACGAACTTTAAAATCTGTGTGGCTGTCACTCGGCTGCATGCTTAGTGCACTCACGCAGTATAATTAATAACTAATTACTGTCGTTGACAGGACACGAGTAACTCGTCTATCTTCTGCAGGCTGCTTACGGTTTCGTCCGTGTTGCAGCCGATCATCAGCACATCTAGGTTTCGTCCGGGTGTGACCGAAAGGTAAGATGGAGAGCCTTGTCCCTGGTTTCAACGAGAAAACACACGTCCAACTCAGTTTGCCTGTTTTACAGGTTCGCGACGTGCTCGTACGTGGCTTTGGAGACTCCGTGGAGGAGGTCTTATCAGAGGCACGTCAACATCTTAAAGATGGCACTTGTGGCTTAGTAGAAGTTGAAAAAGGCGTTTTGCCTCAACTTGAACAGCCCTATGTGTTCAT")
+	manifestURL   = "https://raw.githubusercontent.com/artic-network/primer-schemes/master/schemes_manifest.json"
+	primersURL    = "https://github.com/artic-network/primer-schemes/raw/master/nCoV-2019/V3/nCoV-2019.primer.bed"
+	refURL        = "https://github.com/artic-network/primer-schemes/raw/master/nCoV-2019/V3/nCoV-2019.reference.fasta"
+	localRoot     = "testdata/primer-schemes"
+	localManifest = "schemes_manifest.json"
+	localPrimers  = filepath.Join(localRoot, "test/V1/test.primer.bed")
+	localRef      = filepath.Join(localRoot, "test/V1/test.reference.fasta")
+	localRead     = []byte("CGATCCGTAGGGGCAGCGCAGTATGCCAAGACTATAGGCACTGTCGCATCACAAACGATTAACTGATAAATGAGCCCTTTATGACACGGGCATATGACTGGTTTACGATAGTATGTCCAACGGCGAGCTTTACATTTGCTGTGAGAGGTACAGGGATTAGTGAGAAGCCGTGCGTATCAATTCGTACCTTGGGGGTCGTTACCACTCTGTTCCCACGAGCGG")
 )
 
+// loadTestSet will collect and read primers and reference
+// sequence into a new AmpliconSet, the same way as
+// NewAmpliconSet does.
+func loadTestSet(primersLocation, refLocation string) (*AmpliconSet, error) {
+	as := newAmpliconSet()
+	primers, err := getResource(primersLocation, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if err := readPrimers(as, bytes.NewReader(primers)); err != nil {
+		return nil, err
+	}
+	reference, err := getResource(refLocation, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if err := readSequence(as, bytes.NewReader(reference)); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
 // TestGetManifest
 func TestGetManifest(t *testing.T) {
-	if _, err := GetManifest(manifestURL); err != nil {
+	if _, err := GetManifest(localManifest, SetLocalRoot(localRoot)); err != nil {
 		t.Fatal(err)
 	}
 }
 
 // TestCheckManifest
 func TestCheckManifest(t *testing.T) {
-	man, err := GetManifest(manifestURL)
+	man, err := GetManifest(localManifest, SetLocalRoot(localRoot))
 	if err != nil {
 		t.Fatal(err)
 	}
-	tag, err := CheckManifest(man, "scov2", 1)
+	tag, err := CheckManifest(man, "test-scheme", 1)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if tag != "sars-cov-2" {
-		t.Fatalf("incorrrect tag returned from manifest: wanted sars-cov-2, got %v", tag)
+	if tag != "test" {
+		t.Fatalf("incorrrect tag returned from manifest: wanted test, got %v", tag)
 	}
 }
 
 // TestGetPrimers
 func TestGetPrimers(t *testing.T) {
-	a := make(AmpliconSet)
-	if err := getPrimers(a, primersURL); err != nil {
+	a := newAmpliconSet()
+	primers, err := getResource(localPrimers, "", "")
+	if err != nil {
 		t.Fatal(err)
 	}
-	if len(a) != numSCOV2amplicons {
-		t.Fatalf("incorrect number of amplicons generated from SARS-COV-2 (v3) scheme: wanted 98, got %d", len(a))
+	if err := readPrimers(a, bytes.NewReader(primers)); err != nil {
+		t.Fatal(err)
+	}
+	if a.GetNumAmplicons() != 3 {
+		t.Fatalf("incorrect number of amplicons generated from test scheme: wanted 3, got %d", a.GetNumAmplicons())
 	}
-	if a.GetMeanSize() != 393 {
-		t.Fatalf("did not get correct mean amplicon size: wanted 393, got %d", a.GetMeanSize())
+	if a.GetMeanSize() != 414 {
+		t.Fatalf("did not get correct mean amplicon size: wanted 414, got %d", a.GetMeanSize())
 	}
 }
 
 // TestGetSequence
 func TestGetSequence(t *testing.T) {
-	a := make(AmpliconSet)
-	if err := getPrimers(a, primersURL); err != nil {
-		t.Fatal(err)
-	}
-	if err := getSequence(a, refURL); err != nil {
+	a, err := loadTestSet(localPrimers, localRef)
+	if err != nil {
 		t.Fatal(err)
 	}
-	for name, amp := range a {
+	for name, amp := range a.amplicons {
 		if len(amp.sequence) == 0 {
 			t.Fatalf("no sequence produced for amplicon: %v", name)
 		}
@@ -66,42 +96,215 @@ func TestGetSequence(t *testing.T) {
 
 // TestGetSketch
 func TestGetSketch(t *testing.T) {
-	a := make(AmpliconSet)
-	if err := getPrimers(a, primersURL); err != nil {
-		t.Fatal(err)
-	}
-	if err := getSequence(a, refURL); err != nil {
+	a, err := loadTestSet(localPrimers, localRef)
+	if err != nil {
 		t.Fatal(err)
 	}
-	for _, amplicon := range a {
-		if err := amplicon.getSketch(); err != nil {
+	for _, amplicon := range a.amplicons {
+		if err := amplicon.getSketch(a.sketchParams); err != nil {
 			t.Fatal(err)
 		}
-		if len(amplicon.sketch.GetSketch()) != sketchSize {
-			t.Fatalf("sketch was not expected size: wanted %d, got %d", sketchSize, len(amplicon.sketch.GetSketch()))
+		if len(amplicon.sketch.GetSketch()) != DefaultSketchSize {
+			t.Fatalf("sketch was not expected size: wanted %d, got %d", DefaultSketchSize, len(amplicon.sketch.GetSketch()))
 		}
 	}
 }
 
 // TestGetTopHit
 func TestGetTopHit(t *testing.T) {
-	a := make(AmpliconSet)
-	if err := getPrimers(a, primersURL); err != nil {
+	a, err := loadTestSet(localPrimers, localRef)
+	if err != nil {
 		t.Fatal(err)
 	}
-	if err := getSequence(a, refURL); err != nil {
+	for _, amplicon := range a.amplicons {
+		if err := amplicon.getSketch(a.sketchParams); err != nil {
+			t.Fatal(err)
+		}
+	}
+	topHit, _, err := a.GetTopHit(localRead, api.Scoring_JACCARD)
+	if err != nil {
 		t.Fatal(err)
 	}
-	for _, amplicon := range a {
-		if err := amplicon.getSketch(); err != nil {
-			t.Fatal(err)
+	if topHit != "2" {
+		t.Fatalf("incorrect amplicon return for top hit: wanted 2, got %s", topHit)
+	}
+}
+
+// TestResolveLocation
+func TestResolveLocation(t *testing.T) {
+	tests := []struct {
+		location string
+		root     string
+		resolved string
+	}{
+		{manifestURL, "", manifestURL},
+		{manifestURL, "/data/primer-schemes", "/data/primer-schemes/schemes_manifest.json"},
+		{primersURL, "/data/primer-schemes", "/data/primer-schemes/nCoV-2019/V3/nCoV-2019.primer.bed"},
+		{"nCoV-2019/V3/nCoV-2019.primer.bed", "/data/primer-schemes", "/data/primer-schemes/nCoV-2019/V3/nCoV-2019.primer.bed"},
+		{"/other/scheme.bed", "/data/primer-schemes", "/other/scheme.bed"},
+		{"file:///other/scheme.bed", "/data/primer-schemes", "file:///other/scheme.bed"},
+		{"https://example.com/scheme.bed", "/data/primer-schemes", "https://example.com/scheme.bed"},
+	}
+	for i, test := range tests {
+		if resolved := resolveLocation(test.location, test.root); resolved != test.resolved {
+			t.Fatalf("test %d: wanted %v, got %v", i, test.resolved, resolved)
+		}
+	}
+}
+
+// TestLocalAmpliconSet
+func TestLocalAmpliconSet(t *testing.T) {
+	man, err := GetManifest(localManifest, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := CheckManifest(man, "test-scheme", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// file URLs should also work without a root
+	absRoot, err := filepath.Abs(localRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetManifest("file://" + filepath.ToSlash(filepath.Join(absRoot, localManifest))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewAmpliconSet(man, tag, 1); err == nil {
+		t.Fatal("expected relative scheme paths to fail without a local root")
+	}
+	if _, err := NewAmpliconSet(man, tag, 2, SetLocalRoot(localRoot)); err == nil {
+		t.Fatal("expected missing scheme version to fail")
+	}
+
+	// build the amplicon set from the local files
+	as, err := NewAmpliconSet(man, tag, 1, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if as.GetNumAmplicons() != 3 {
+		t.Fatalf("incorrect number of amplicons generated from test scheme: wanted 3, got %d", as.GetNumAmplicons())
+	}
+	if as.GetMeanSize() != 414 {
+		t.Fatalf("did not get correct mean amplicon size: wanted 414, got %d", as.GetMeanSize())
+	}
+	topHit, _, err := as.GetTopHit(localRead, api.Scoring_JACCARD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topHit != "2" {
+		t.Fatalf("incorrect amplicon return for top hit: wanted 2, got %s", topHit)
+	}
+
+	// a short read from an amplicon should be fully contained in it
+	topHit, score, err := as.GetTopHit(localRead[100:160], api.Scoring_CONTAINMENT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topHit != "2" || score != 1.0 {
+		t.Fatalf("incorrect containment top hit: wanted 2 (1.0), got %s (%f)", topHit, score)
+	}
+	if _, _, err := as.GetTopHit(localRead, api.Scoring(-1)); err == nil {
+		t.Fatal("expected unsupported scoring to fail")
+	}
+
+	// amplicons 1 (30-424) and 2 (350-774) overlap on the 1200 base reference
+	if as.GetReferenceLength() != 1200 {
+		t.Fatalf("incorrect reference length: wanted 1200, got %d", as.GetReferenceLength())
+	}
+	tests := []struct {
+		amplicons []string
+		coverage  float64
+	}{
+		{nil, 0},
+		{[]string{"1", "missing"}, 394.0 / 1200},
+		{[]string{"2", "1"}, 744.0 / 1200},
+		{[]string{"1", "3"}, 818.0 / 1200},
+		{[]string{"1", "2", "3"}, 1094.0 / 1200},
+	}
+	for i, test := range tests {
+		if coverage := as.GetReferenceCoverage(test.amplicons); coverage != test.coverage {
+			t.Fatalf("test %d: wanted reference coverage %f, got %f", i, test.coverage, coverage)
+		}
+	}
+}
+
+// TestSketchParams
+func TestSketchParams(t *testing.T) {
+	man, err := GetManifest(localManifest, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sp := range []SketchParams{{0, 24, true}, {MaxKmerSize + 1, 24, true}, {7, 0, true}} {
+		if _, err := NewAmpliconSet(man, "test", 1, SetLocalRoot(localRoot), SetSketchParams(sp)); err == nil {
+			t.Fatalf("expected bad sketch params to fail: %v", sp)
 		}
 	}
-	topHit, _, err := a.GetTopHit(queryRead)
+
+	// build the set with some non-default params
+	sp := SketchParams{KmerSize: 11, SketchSize: 50, Canonical: false}
+	if sp.String() == DefaultSketchParams().String() {
+		t.Fatal("sketch params do not have unique descriptions")
+	}
+	as, err := NewAmpliconSet(man, "test", 1, SetLocalRoot(localRoot), SetSketchParams(sp))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if topHit != "1" {
-		t.Logf("incorrect amplicon return for top hit: wanted 1, got %s", topHit)
+	if as.GetSketchParams() != sp {
+		t.Fatalf("amplicon set has wrong sketch params: wanted %v, got %v", sp, as.GetSketchParams())
+	}
+	for name, amplicon := range as.amplicons {
+		if len(amplicon.sketch.GetSketch()) != sp.SketchSize {
+			t.Fatalf("sketch for amplicon %v was not expected size: wanted %d, got %d", name, sp.SketchSize, len(amplicon.sketch.GetSketch()))
+		}
+	}
+	topHit, _, err := as.GetTopHit(localRead, api.Scoring_JACCARD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topHit != "2" {
+		t.Fatalf("incorrect amplicon return for top hit: wanted 2, got %s", topHit)
+	}
+}
+
+// TestSegmentedScheme will check that amplicons with
+// the same number on different references are kept
+// apart.
+func TestSegmentedScheme(t *testing.T) {
+	man, err := GetManifest(localManifest, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	as, err := NewAmpliconSet(man, "test-segmented", 1, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := as.GetAmpliconNames()
+	if len(names) != 4 || names[0] != "seg1:1" || names[3] != "seg2:2" {
+		t.Fatalf("amplicons were not keyed by reference: %v", names)
+	}
+	if refs := as.GetReferenceNames(); len(refs) != 2 || as.GetReference("seg2:1") != "seg2" || as.GetSequenceLength("seg1") != 600 || as.GetReferenceLength() != 1200 {
+		t.Fatalf("incorrect references: %v", refs)
+	}
+
+	// each amplicon should be sketched from its own reference
+	for _, name := range names {
+		topHit, score, err := as.GetTopHit(as.amplicons[name].sequence, api.Scoring_JACCARD)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if topHit != name || score != 1.0 {
+			t.Fatalf("incorrect top hit for %v: got %v (%f)", name, topHit, score)
+		}
+	}
+	if as.Overlap("seg1:2", "seg2:1") || !as.Overlap("seg2:1", "seg2:2") {
+		t.Fatal("incorrect overlaps across references")
+	}
+
+	// seg1:1 covers 30-324 of seg1, seg2:1 and seg2:2 cover 20-574 of seg2
+	coverage := as.GetCoverageByReference([]string{"seg1:1", "seg2:1", "seg2:2"})
+	if len(coverage) != 2 || coverage["seg1"] != 294.0/600 || coverage["seg2"] != 554.0/600 {
+		t.Fatalf("incorrect coverage by reference: %v", coverage)
 	}
 }
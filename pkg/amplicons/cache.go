@@ -0,0 +1,124 @@
+package amplicons
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	cachedManifest  = "schemes_manifest.json"
+	cachedPrimers   = "primer.bed"
+	cachedReference = "reference.fasta"
+)
+
+// ErrChecksumMismatch is returned when a primer scheme file does not match the manifest checksum
+var ErrChecksumMismatch = errors.New("sha256 checksum does not match the manifest")
+
+// getResource will collect a primer scheme file and check
+// it against the expected SHA256 checksum (if provided).
+//
+// If a cache path is provided, remote files are read from
+// the cache if they are present and match the checksum.
+// Otherwise they are downloaded, checked and then added to
+// the cache. Local files are never cached.
+func getResource(location, checksum, cachePath string) ([]byte, error) {
+	cacheable := len(cachePath) != 0 && isRemote(location)
+
+	// try the cache first
+	if cacheable {
+		data, err := ioutil.ReadFile(cachePath)
+		switch {
+		case err == nil && verifyChecksum(data, checksum) == nil:
+			log.Tracef("using cached copy of %v: %v", location, cachePath)
+			return data, nil
+		case err == nil:
+			log.Warnf("cached copy of %v does not match the manifest checksum, downloading it again", location)
+		case !os.IsNotExist(err):
+			log.Warnf("could not read cached copy of %v: %v", location, err)
+		}
+	}
+
+	// collect the file and check it
+	data, err := readLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyChecksum(data, checksum); err != nil {
+		return nil, fmt.Errorf("could not use %v: %w", location, err)
+	}
+
+	// update the cache
+	if cacheable {
+		if err := writeCacheFile(cachePath, data); err != nil {
+			log.Warnf("could not cache %v: %v", location, err)
+		}
+	}
+	return data, nil
+}
+
+// verifyChecksum will check the SHA256 checksum
+// of some data. An empty checksum is not checked.
+func verifyChecksum(data []byte, checksum string) error {
+	if len(checksum) == 0 {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, checksum) {
+		return fmt.Errorf("%w (wanted %v, got %v)", ErrChecksumMismatch, checksum, got)
+	}
+	return nil
+}
+
+// writeCacheFile will write data to the cache, via
+// a temporary file so that a partial file is never
+// read back from the cache.
+func writeCacheFile(cachePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(cachePath), ".archer-cache-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), cachePath); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// getCachePath returns the cache path for a scheme
+// file, or an empty string if there is no cache.
+func getCachePath(cacheDir, scheme, version, file string) string {
+	if len(cacheDir) == 0 {
+		return ""
+	}
+	return filepath.Join(cacheDir, filepath.Base(scheme), "V"+version, file)
+}
+
+// isRemote checks if a location needs downloading.
+func isRemote(location string) bool {
+	u, err := url.Parse(location)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
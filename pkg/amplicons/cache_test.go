@@ -0,0 +1,120 @@
+package amplicons
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// getChecksum returns the SHA256 checksum for a test file.
+func getChecksum(t *testing.T, file string) string {
+	data, err := ioutil.ReadFile(filepath.Join(localRoot, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// TestSchemeCache
+func TestSchemeCache(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "archer-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	// serve the test scheme, counting the downloads
+	var downloads int32
+	fileServer := http.FileServer(http.Dir(localRoot))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&downloads, 1)
+		fileServer.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	// cache the manifest and check it's used once the server is gone
+	manifestURL := server.URL + "/" + localManifest
+	if _, err := GetManifest(manifestURL, SetCacheDir(cacheDir)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, cachedManifest)); err != nil {
+		t.Fatalf("manifest was not cached: %v", err)
+	}
+
+	// create a manifest pointing at the server, with the checksums
+	man := &api.Manifest{
+		Schemes: map[string]*api.SchemeMetadata{
+			"test": {
+				Aliases:                  []string{"test"},
+				LatestVersion:            1,
+				PrimerUrls:               map[string]string{"1": server.URL + "/test/V1/test.primer.bed"},
+				ReferenceUrls:            map[string]string{"1": server.URL + "/test/V1/test.reference.fasta"},
+				PrimerSha256Checksums:    map[string]string{"1": getChecksum(t, "test/V1/test.primer.bed")},
+				ReferenceSha256Checksums: map[string]string{"1": getChecksum(t, "test/V1/test.reference.fasta")},
+			},
+		},
+	}
+
+	// a bad checksum should be rejected and not cached
+	badMan := &api.Manifest{}
+	data, err := json.Marshal(man)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, badMan); err != nil {
+		t.Fatal(err)
+	}
+	badMan.Schemes["test"].PrimerSha256Checksums["1"] = getChecksum(t, "test/V1/test.reference.fasta")
+	if _, err := NewAmpliconSet(badMan, "test", 1, SetCacheDir(cacheDir)); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected checksum mismatch, got: %v", err)
+	}
+	if _, err := os.Stat(getCachePath(cacheDir, "test", "1", cachedPrimers)); !os.IsNotExist(err) {
+		t.Fatal("file with bad checksum was cached")
+	}
+
+	// download and cache the scheme
+	if _, err := NewAmpliconSet(man, "test", 1, SetCacheDir(cacheDir)); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{cachedPrimers, cachedReference} {
+		if _, err := os.Stat(getCachePath(cacheDir, "test", "1", file)); err != nil {
+			t.Fatalf("scheme file was not cached: %v", err)
+		}
+	}
+
+	// now use the cache without the server
+	server.Close()
+	before := atomic.LoadInt32(&downloads)
+	if _, err := GetManifest(manifestURL, SetCacheDir(cacheDir)); err != nil {
+		t.Fatal(err)
+	}
+	as, err := NewAmpliconSet(man, "test", 1, SetCacheDir(cacheDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if as.GetNumAmplicons() != 3 {
+		t.Fatalf("incorrect number of amplicons generated from cached scheme: wanted 3, got %d", as.GetNumAmplicons())
+	}
+	if atomic.LoadInt32(&downloads) != before {
+		t.Fatal("cached scheme was downloaded again")
+	}
+
+	// a corrupted cache should not be used
+	if err := ioutil.WriteFile(getCachePath(cacheDir, "test", "1", cachedPrimers), []byte("corrupt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewAmpliconSet(man, "test", 1, SetCacheDir(cacheDir)); err == nil {
+		t.Fatal("expected corrupted cache to be ignored")
+	}
+}
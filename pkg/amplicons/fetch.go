@@ -0,0 +1,90 @@
+package amplicons
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolveLocation will convert a primer scheme resource
+// location to a local path if a local copy of the
+// primer-schemes repository is available.
+//
+// If no local root is provided, the location is
+// returned unchanged. Otherwise, relative paths are
+// resolved against the root and GitHub URLs for files
+// in the repository are converted to paths within the
+// root. Other locations are returned unchanged.
+func resolveLocation(location, root string) string {
+	if len(root) == 0 {
+		return location
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+	switch u.Scheme {
+	case "":
+		if filepath.IsAbs(location) {
+			return location
+		}
+		return filepath.Join(root, filepath.FromSlash(location))
+	case "http", "https":
+
+		// drop the owner, repository and branch from GitHub URLs
+		parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+		switch {
+		case u.Host == "raw.githubusercontent.com" && len(parts) > 3:
+			return filepath.Join(root, filepath.Join(parts[3:]...))
+		case u.Host == "github.com" && len(parts) > 4 && (parts[2] == "raw" || parts[2] == "blob"):
+			return filepath.Join(root, filepath.Join(parts[4:]...))
+		}
+	}
+	return location
+}
+
+// openLocation will open a primer scheme resource, which
+// can be an http(s) URL, a file:// URL or a local path.
+// The caller must close the returned reader.
+func openLocation(location string) (io.ReadCloser, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse location (%v): %w", location, err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		resp, err := http.Get(location)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("could not download %v: %v", location, resp.Status)
+		}
+		return resp.Body, nil
+	case "file":
+		if len(u.Host) != 0 && u.Host != "localhost" {
+			return nil, fmt.Errorf("file URL must be a local path: %v", location)
+		}
+		return os.Open(filepath.FromSlash(u.Path))
+	case "":
+		return os.Open(location)
+	default:
+		return nil, fmt.Errorf("unsupported location scheme (%v), must be http(s)://, file:// or a path", u.Scheme)
+	}
+}
+
+// readLocation will read all of a primer scheme resource.
+func readLocation(location string) ([]byte, error) {
+	body, err := openLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return ioutil.ReadAll(body)
+}
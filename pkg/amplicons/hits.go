@@ -0,0 +1,80 @@
+package amplicons
+
+import (
+	"sort"
+)
+
+// Hit is an amplicon matched by a read
+// and the score for the match.
+type Hit struct {
+	Amplicon string
+	Score    float64
+}
+
+// HitType classifies a read from the amplicons
+// it matches.
+type HitType int
+
+const (
+
+	// UniqueHit is a read that matches one amplicon much better than any other
+	UniqueHit HitType = iota
+
+	// AmbiguousHit is a read that matches overlapping amplicons almost as well (e.g. a read from an overlap region)
+	AmbiguousHit
+
+	// ChimericHit is a read that matches amplicons that don't overlap almost as well (e.g. a ligation or chimera artefact)
+	ChimericHit
+)
+
+// String returns the name of a HitType.
+func (ht HitType) String() string {
+	switch ht {
+	case UniqueHit:
+		return "unique"
+	case AmbiguousHit:
+		return "ambiguous"
+	case ChimericHit:
+		return "chimeric"
+	default:
+		return "unknown"
+	}
+}
+
+// rankHits sorts hits by score, breaking
+// ties by amplicon name.
+func rankHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Amplicon < hits[j].Amplicon
+	})
+}
+
+// ClassifyHits will classify a read from its ranked hits.
+// A read is unique unless its second best score is at
+// least ratio times the best score, in which case it is
+// ambiguous if the two amplicons overlap on the reference,
+// or chimeric if they don't.
+func (as *AmpliconSet) ClassifyHits(hits []Hit, ratio float64) HitType {
+	if len(hits) < 2 || hits[1].Score == 0 || hits[1].Score < ratio*hits[0].Score {
+		return UniqueHit
+	}
+	if as.Overlap(hits[0].Amplicon, hits[1].Amplicon) {
+		return AmbiguousHit
+	}
+	return ChimericHit
+}
+
+// Overlap returns true if two amplicons in the
+// set overlap on the reference (including their
+// primers).
+func (as *AmpliconSet) Overlap(amplicon1, amplicon2 string) bool {
+	a1, ok1 := as.amplicons[amplicon1]
+	a2, ok2 := as.amplicons[amplicon2]
+	if !ok1 || !ok2 || a1.refName != a2.refName {
+		return false
+	}
+	return a1.start < a2.end && a2.start < a1.end
+}
@@ -0,0 +1,54 @@
+package amplicons
+
+import (
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// TestClassifyHits
+func TestClassifyHits(t *testing.T) {
+	man, err := GetManifest(localManifest, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	as, err := NewAmpliconSet(man, "test", 1, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !as.Overlap("1", "2") || as.Overlap("1", "3") || as.Overlap("1", "missing") {
+		t.Fatal("incorrect amplicon overlaps")
+	}
+
+	// amplicons 1 and 2 overlap by 74 bases, amplicons 1 and 3 don't overlap
+	amp1, amp3 := as.amplicons["1"].sequence, as.amplicons["3"].sequence
+	chimera := append(append([]byte{}, amp1...), amp3...)
+	tests := []struct {
+		read    []byte
+		scoring api.Scoring
+		hitType HitType
+	}{
+		{amp1, api.Scoring_JACCARD, UniqueHit},
+		{amp1[320:], api.Scoring_CONTAINMENT, AmbiguousHit},
+		{chimera, api.Scoring_CONTAINMENT, ChimericHit},
+	}
+	for i, test := range tests {
+		hits, err := as.GetHits(test.read, test.scoring, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hits) == 0 || len(hits) > 2 {
+			t.Fatalf("test %d: wanted 1 or 2 hits, got %v", i, hits)
+		}
+		if hitType := as.ClassifyHits(hits, 0.6); hitType != test.hitType {
+			t.Fatalf("test %d: wanted %v read, got %v (%v)", i, test.hitType, hitType, hits)
+		}
+	}
+
+	// hits should be ranked, with ties broken by name
+	hits := []Hit{{"3", 0.5}, {"2", 0.9}, {"1", 0.5}}
+	rankHits(hits)
+	if hits[0].Amplicon != "2" || hits[1].Amplicon != "1" || hits[2].Amplicon != "3" {
+		t.Fatalf("hits were not ranked: %v", hits)
+	}
+}
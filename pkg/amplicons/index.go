@@ -0,0 +1,126 @@
+package amplicons
+
+import (
+	"sort"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// sketchIndex is an inverted index of the sketch
+// values for a set of amplicons. Each sketch value
+// is linked to the amplicons with that value in
+// their sketch, so that a query only needs to be
+// compared against amplicons it shares values with.
+type sketchIndex struct {
+	names     []string             // sorted amplicon names
+	lengths   []int                // sketch length for each amplicon
+	maxValues []uint64             // largest sketch value for each amplicon
+	postings  map[uint64][]posting // sketch value -> amplicons
+}
+
+// posting records an amplicon containing a sketch
+// value and the number of times the value occurs.
+type posting struct {
+	amplicon int
+	count    int
+}
+
+// newSketchIndex will index the sketches for a set
+// of amplicons. The amplicons must be sketched.
+func newSketchIndex(amplicons map[string]*Amplicon) *sketchIndex {
+	idx := &sketchIndex{
+		names:     make([]string, 0, len(amplicons)),
+		lengths:   make([]int, len(amplicons)),
+		maxValues: make([]uint64, len(amplicons)),
+		postings:  make(map[uint64][]posting),
+	}
+	for name := range amplicons {
+		idx.names = append(idx.names, name)
+	}
+	sort.Strings(idx.names)
+	for i, name := range idx.names {
+		if amplicons[name].sketch == nil {
+			continue
+		}
+		sketch := amplicons[name].sketch.GetSketch()
+		idx.lengths[i] = len(sketch)
+		if len(sketch) != 0 {
+			idx.maxValues[i] = sketch[len(sketch)-1]
+		}
+		for _, run := range countValues(sketch) {
+			idx.postings[run.value] = append(idx.postings[run.value], posting{amplicon: i, count: run.count})
+		}
+	}
+	return idx
+}
+
+// query will return the amplicons sharing values with
+// a query sketch, ranked by score, giving the same result
+// as comparing the query against every amplicon sketch.
+// The sketch must be sorted.
+func (idx *sketchIndex) query(sketch []uint64, scoring api.Scoring) []Hit {
+
+	// count the shared values for each amplicon
+	intersects := make(map[int]int)
+	for _, run := range countValues(sketch) {
+		for _, p := range idx.postings[run.value] {
+			if p.count < run.count {
+				intersects[p.amplicon] += p.count
+			} else {
+				intersects[p.amplicon] += run.count
+			}
+		}
+	}
+
+	// score and rank the amplicons
+	hits := make([]Hit, 0, len(intersects))
+	for amplicon, intersect := range intersects {
+		if score := idx.score(amplicon, intersect, sketch, scoring); score > 0 {
+			hits = append(hits, Hit{Amplicon: idx.names[amplicon], Score: score})
+		}
+	}
+	rankHits(hits)
+	return hits
+}
+
+// score returns the score for an amplicon from the
+// number of sketch values it shares with the query.
+func (idx *sketchIndex) score(amplicon, intersect int, sketch []uint64, scoring api.Scoring) float64 {
+	switch scoring {
+	case api.Scoring_CONTAINMENT:
+
+		// only query values up to the amplicon's largest value can be compared
+		compared := sort.Search(len(sketch), func(i int) bool { return sketch[i] > idx.maxValues[amplicon] })
+		if compared == 0 {
+			return 0.0
+		}
+		return float64(intersect) / float64(compared)
+	default:
+		maxLen := idx.lengths[amplicon]
+		if maxLen < len(sketch) {
+			maxLen = len(sketch)
+		}
+		return float64(intersect) / float64(maxLen)
+	}
+}
+
+// valueCount is a sketch value and the number of
+// times it occurs in a sketch.
+type valueCount struct {
+	value uint64
+	count int
+}
+
+// countValues collapses a sorted sketch into
+// its distinct values and their counts.
+func countValues(sketch []uint64) []valueCount {
+	counts := make([]valueCount, 0, len(sketch))
+	for _, value := range sketch {
+		if n := len(counts); n > 0 && counts[n-1].value == value {
+			counts[n-1].count++
+			continue
+		}
+		counts = append(counts, valueCount{value: value, count: 1})
+	}
+	return counts
+}
@@ -0,0 +1,105 @@
+package amplicons
+
+import (
+	"math/rand"
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// getTestReads returns overlapping reads from each
+// amplicon in a set, plus some random reads.
+func getTestReads(as *AmpliconSet, readLen, step int) [][]byte {
+	reads := [][]byte{}
+	for _, name := range as.GetAmpliconNames() {
+		seq := as.amplicons[name].sequence
+		for i := 0; i+readLen <= len(seq); i += step {
+			reads = append(reads, seq[i:i+readLen])
+		}
+	}
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		read := make([]byte, readLen)
+		for j := range read {
+			read[j] = "ACGT"[rng.Intn(4)]
+		}
+		reads = append(reads, read)
+	}
+	return reads
+}
+
+// TestSketchIndex
+func TestSketchIndex(t *testing.T) {
+	man, err := GetManifest(localManifest, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	as, err := NewAmpliconSet(man, "test", 1, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if as.index == nil {
+		t.Fatal("amplicon set was not indexed")
+	}
+
+	// the index should give the same result as a full scan
+	for _, scoring := range []api.Scoring{api.Scoring_JACCARD, api.Scoring_CONTAINMENT} {
+		for i, read := range getTestReads(as, 60, 7) {
+			sketcher, err := as.sketchParams.sketch(read)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantHits, err := as.getHitsScan(sketcher, scoring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotHits, err := as.GetHits(read, scoring, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(gotHits) != len(wantHits) {
+				t.Fatalf("%v read %d: index returned %v, scan returned %v", scoring, i, gotHits, wantHits)
+			}
+			for j := range gotHits {
+				if gotHits[j] != wantHits[j] {
+					t.Fatalf("%v read %d: index returned %v, scan returned %v", scoring, i, gotHits, wantHits)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkGetTopHit compares the indexed and
+// full scan top hit searches for the SARS-CoV-2
+// V3 scheme.
+func BenchmarkGetTopHit(b *testing.B) {
+	as, err := loadTestSet(primersURL, refURL)
+	if err != nil {
+		b.Skipf("could not get SARS-CoV-2 V3 scheme: %v", err)
+	}
+	for _, amplicon := range as.amplicons {
+		if err := amplicon.getSketch(as.sketchParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+	as.index = newSketchIndex(as.amplicons)
+	reads := getTestReads(as, 250, 50)
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sketcher, err := as.sketchParams.sketch(reads[i%len(reads)])
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := as.getHitsScan(sketcher, api.Scoring_JACCARD); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := as.GetTopHit(reads[i%len(reads)], api.Scoring_JACCARD); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
+	"io/ioutil"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
 
 	api "github.com/will-rowe/archer/pkg/api/v1"
 )
@@ -18,27 +21,55 @@ var (
 	ErrNoSchemeVersion = errors.New("request scheme version must be >= 0")
 )
 
-// GetManifest will download the ARTIC primer scheme
+// GetManifest will collect the ARTIC primer scheme
 // manifest and populate an in memory manifest.
-func GetManifest(manifestURL string) (*api.Manifest, error) {
+// The manifest can be a URL or a local file, which
+// can be relative to a local primer-schemes root.
+//
+// If a cache directory is set, a downloaded manifest
+// is cached and the cached copy is used if a later
+// download fails.
+func GetManifest(manifestURL string, opts ...Option) (*api.Manifest, error) {
+	o, err := getOptions(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	// create an empty manifest to populate
 	manifest := &api.Manifest{
 		Schemes: make(map[string]*api.SchemeMetadata),
 	}
 
-	// download the json
-	resp, err := http.Get(manifestURL)
+	// get the json, falling back to any cached copy
+	location := resolveLocation(manifestURL, o.localRoot)
+	cachePath := ""
+	if len(o.cacheDir) != 0 && isRemote(location) {
+		cachePath = filepath.Join(o.cacheDir, cachedManifest)
+	}
+	data, err := readLocation(location)
 	if err != nil {
-		return nil, err
+		if len(cachePath) == 0 {
+			return nil, err
+		}
+		cached, cacheErr := ioutil.ReadFile(cachePath)
+		if cacheErr != nil {
+			return nil, err
+		}
+		log.Warnf("could not collect manifest (%v), using cached copy: %v", err, cachePath)
+		data, cachePath = cached, ""
 	}
-	defer resp.Body.Close()
 
 	// decode the json into the manifest struct
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(manifest); err != nil {
+	if err := json.Unmarshal(data, manifest); err != nil {
 		return nil, err
 	}
+
+	// update the cache
+	if len(cachePath) != 0 {
+		if err := writeCacheFile(cachePath, data); err != nil {
+			log.Warnf("could not cache manifest: %v", err)
+		}
+	}
 	return manifest, nil
 }
 
@@ -0,0 +1,185 @@
+package amplicons
+
+import (
+	"fmt"
+)
+
+const (
+
+	// primerSearchSlack is the number of bases beyond the primer length
+	// searched at each end of a read (e.g. for leftover adapter or barcode)
+	primerSearchSlack = 50
+
+	// primerMaxErrorRate is the maximum fraction of a primer's length that
+	// can be edits in a match
+	primerMaxErrorRate = 0.2
+)
+
+// primer is a single primer from a scheme.
+type primer struct {
+	name     string // primer name from the BED (e.g. nCoV-2019_1_LEFT)
+	start    int    // start on the reference (0-based indexing)
+	end      int    // end on the reference
+	reverse  bool   // primer is on the reverse strand (a right primer)
+	sequence []byte // primer sequence (5' to 3')
+}
+
+// PrimerSites records where the primers of
+// an amplicon were found in a read.
+type PrimerSites struct {
+	InsertStart int  // first base after the primer at the start of the read (0 if none found)
+	InsertEnd   int  // first base of the primer at the end of the read (read length if none found)
+	StartFound  bool // a primer was found at the start of the read
+	EndFound    bool // a primer was found at the end of the read
+}
+
+// FindPrimers will search the ends of a read for the primers
+// of an amplicon and return the sites of any primers found.
+//
+// A read can come from either strand, so the start of the
+// read is searched for each left and right primer, and the
+// end of the read for the reverse complement of the primers
+// from the other side of the amplicon. Matches can contain
+// a few edits and can be offset from the read ends.
+func (as *AmpliconSet) FindPrimers(ampliconName string, read []byte) (PrimerSites, error) {
+	amplicon, ok := as.amplicons[ampliconName]
+	if !ok {
+		return PrimerSites{}, fmt.Errorf("amplicon not found in set: %v", ampliconName)
+	}
+	sites := PrimerSites{
+		InsertStart: 0,
+		InsertEnd:   len(read),
+	}
+
+	// search the start of the read, which gives the read strand
+	leftEnd, leftDist, leftOK := searchStart(amplicon.leftPrimers, read)
+	rightEnd, rightDist, rightOK := searchStart(amplicon.rightPrimers, read)
+	var endPrimers []*primer
+	switch {
+	case leftOK && (!rightOK || leftDist <= rightDist):
+		sites.InsertStart, sites.StartFound = leftEnd, true
+		endPrimers = amplicon.rightPrimers
+	case rightOK:
+		sites.InsertStart, sites.StartFound = rightEnd, true
+		endPrimers = amplicon.leftPrimers
+	default:
+		endPrimers = append(append([]*primer{}, amplicon.leftPrimers...), amplicon.rightPrimers...)
+	}
+
+	// search the end of the read, ignoring any match overlapping the start primer
+	if insertEnd, ok := searchEnd(endPrimers, read); ok && insertEnd >= sites.InsertStart {
+		sites.InsertEnd, sites.EndFound = insertEnd, true
+	}
+	return sites, nil
+}
+
+// searchStart will search the start of a read for a set of
+// primers and return the end of the best match and its
+// number of edits.
+func searchStart(primers []*primer, read []byte) (int, int, bool) {
+	bestEnd, bestDist := 0, -1
+	for _, p := range primers {
+		end, dist, ok := fitPrimer(p.sequence, read[:minInt(len(read), len(p.sequence)+primerSearchSlack)])
+		if ok && (bestDist == -1 || dist < bestDist) {
+			bestEnd, bestDist = end, dist
+		}
+	}
+	return bestEnd, bestDist, bestDist != -1
+}
+
+// searchEnd will search the end of a read for the reverse
+// complement of a set of primers and return the start of
+// the best match.
+func searchEnd(primers []*primer, read []byte) (int, bool) {
+	bestStart, bestDist := len(read), -1
+	for _, p := range primers {
+
+		// the read end holds the primer reverse complement, so reverse the read end
+		// and fit the primer complement
+		window := read[len(read)-minInt(len(read), len(p.sequence)+primerSearchSlack):]
+		end, dist, ok := fitPrimer(complement(p.sequence), reverse(window))
+		if ok && (bestDist == -1 || dist < bestDist) {
+			bestStart, bestDist = len(read)-end, dist
+		}
+	}
+	return bestStart, bestDist != -1
+}
+
+// fitPrimer finds the best match of a primer anywhere in a
+// sequence, allowing for mismatches and indels. It returns
+// the end of the match in the sequence and the number of
+// edits, or false if there is no match within the allowed
+// error rate. Ties go to the match that ends first.
+func fitPrimer(primerSeq, seq []byte) (int, int, bool) {
+	if len(primerSeq) == 0 || len(seq) == 0 {
+		return 0, 0, false
+	}
+	maxDist := int(float64(len(primerSeq)) * primerMaxErrorRate)
+
+	// semi-global alignment, where the primer is fully aligned but the sequence ends are free
+	prev := make([]int, len(seq)+1)
+	curr := make([]int, len(seq)+1)
+	for i := 1; i <= len(primerSeq); i++ {
+		curr[0] = i
+		for j := 1; j <= len(seq); j++ {
+			cost := 1
+			if primerSeq[i-1] == seq[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j-1]+cost, minInt(prev[j]+1, curr[j-1]+1))
+		}
+		prev, curr = curr, prev
+	}
+	bestEnd, bestDist := 0, maxDist+1
+	for j := 1; j <= len(seq); j++ {
+		if prev[j] < bestDist {
+			bestEnd, bestDist = j, prev[j]
+		}
+	}
+	if bestDist > maxDist {
+		return 0, 0, false
+	}
+	return bestEnd, bestDist, true
+}
+
+// complement returns the complement of a sequence.
+func complement(seq []byte) []byte {
+	comp := make([]byte, len(seq))
+	for i, base := range seq {
+		switch base {
+		case 'A':
+			comp[i] = 'T'
+		case 'C':
+			comp[i] = 'G'
+		case 'G':
+			comp[i] = 'C'
+		case 'T':
+			comp[i] = 'A'
+		default:
+			comp[i] = 'N'
+		}
+	}
+	return comp
+}
+
+// reverse returns a reversed copy of a sequence.
+func reverse(seq []byte) []byte {
+	rev := make([]byte, len(seq))
+	for i, base := range seq {
+		rev[len(seq)-1-i] = base
+	}
+	return rev
+}
+
+// revComp returns the reverse complement of a sequence.
+func revComp(seq []byte) []byte {
+	return reverse(complement(seq))
+}
+
+// minInt returns the smaller of two ints.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
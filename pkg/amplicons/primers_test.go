@@ -0,0 +1,111 @@
+package amplicons
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestReadPrimers will check that primers are kept
+// and that missing sequences come from the reference.
+func TestReadPrimers(t *testing.T) {
+	bed, err := ioutil.ReadFile(filepath.Join(localRoot, "test/V1/test.primer.bed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reference, err := ioutil.ReadFile(filepath.Join(localRoot, "test/V1/test.reference.fasta"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// drop the sequence column from a copy of the BED
+	var sixColumn strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(string(bed)), "\n") {
+		fields := strings.Split(line, "\t")
+		sixColumn.WriteString(strings.Join(fields[:6], "\t") + "\n")
+	}
+	for _, primers := range []string{string(bed), sixColumn.String()} {
+		as := newAmpliconSet()
+		if err := readPrimers(as, strings.NewReader(primers)); err != nil {
+			t.Fatal(err)
+		}
+		if err := readSequence(as, bytes.NewReader(reference)); err != nil {
+			t.Fatal(err)
+		}
+		amplicon := as.amplicons["1"]
+		if len(amplicon.leftPrimers) != 1 || len(amplicon.rightPrimers) != 1 {
+			t.Fatalf("wanted a primer pair for amplicon 1, got %d left and %d right", len(amplicon.leftPrimers), len(amplicon.rightPrimers))
+		}
+		left, right := amplicon.leftPrimers[0], amplicon.rightPrimers[0]
+		if left.start != 30 || left.end != 54 || right.start != 400 || right.end != 424 || !right.reverse {
+			t.Fatalf("incorrect primer coordinates: %v %v", left, right)
+		}
+		if string(left.sequence) != "GCACGAAACTTGTTGGCCCAGTGT" || string(right.sequence) != "ATCGCATTGACTTGTCAGGCGGCA" {
+			t.Fatalf("incorrect primer sequences: %s %s", left.sequence, right.sequence)
+		}
+
+		// amplicons 1 and 3 are in pool 1, amplicon 2 is in pool 2
+		if as.GetPool("1") != "1" || as.GetPool("2") != "2" || as.GetPool("3") != "1" || as.GetPool("missing") != "" {
+			t.Fatalf("incorrect primer pools: %v %v %v", as.GetPool("1"), as.GetPool("2"), as.GetPool("3"))
+		}
+		if pools := as.GetPoolNames(); len(pools) != 2 || pools[0] != "1" || pools[1] != "2" {
+			t.Fatalf("incorrect pool names: %v", pools)
+		}
+	}
+
+	// badly formed lines should be rejected rather than panic
+	for _, primers := range []string{
+		"chrom\tstart\tend\tname\tpool\tstrand\n",
+		"ref1\t30\t54\ttest1LEFT\t1\t+\n",
+		"ref1\t30\t54\ttest_1\t1\t+\n",
+		"ref1\t30\t54\ttest_1_MIDDLE\t1\t+\n",
+		"ref1\t30\t54\ttest_1_LEFT\n",
+		"ref1\tstart\t54\ttest_1_LEFT\t1\t+\n",
+	} {
+		if err := readPrimers(newAmpliconSet(), strings.NewReader(primers)); err == nil {
+			t.Fatalf("expected bad primer line to fail: %q", primers)
+		}
+	}
+}
+
+// TestFindPrimers
+func TestFindPrimers(t *testing.T) {
+	man, err := GetManifest(localManifest, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	as, err := NewAmpliconSet(man, "test", 1, SetLocalRoot(localRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// amplicon 1 is 394 bases, with 24 base primers
+	amplicon := as.amplicons["1"].sequence
+	mismatched := append([]byte{}, amplicon...)
+	mismatched[5], mismatched[390] = 'N', 'N'
+	tests := []struct {
+		read  []byte
+		sites PrimerSites
+	}{
+		{amplicon, PrimerSites{24, 370, true, true}},
+		{revComp(amplicon), PrimerSites{24, 370, true, true}},
+		{append([]byte("TTTTTTTTTTTT"), amplicon...), PrimerSites{36, 382, true, true}},
+		{mismatched, PrimerSites{24, 370, true, true}},
+		{amplicon[24:200], PrimerSites{0, 176, false, false}},
+		{amplicon[:200], PrimerSites{24, 200, true, false}},
+	}
+	for i, test := range tests {
+		sites, err := as.FindPrimers("1", test.read)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sites != test.sites {
+			t.Fatalf("test %d: wanted primer sites %+v, got %+v", i, test.sites, sites)
+		}
+	}
+	if _, err := as.FindPrimers("missing", amplicon); err == nil {
+		t.Fatal("expected missing amplicon to fail")
+	}
+}
@@ -30,6 +30,63 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+// QCVerdict is the overall QC result for a sample.
+type QCVerdict int32
+
+const (
+	// the sample has not been checked
+	QCVerdict_NO_VERDICT QCVerdict = 0
+	// the estimated reference coverage is at least the server warn threshold
+	QCVerdict_PASS QCVerdict = 1
+	// the estimated reference coverage is below the server warn threshold
+	QCVerdict_WARN QCVerdict = 2
+	// the estimated reference coverage is below the server fail threshold
+	QCVerdict_FAIL QCVerdict = 3
+)
+
+// Enum value maps for QCVerdict.
+var (
+	QCVerdict_name = map[int32]string{
+		0: "NO_VERDICT",
+		1: "PASS",
+		2: "WARN",
+		3: "FAIL",
+	}
+	QCVerdict_value = map[string]int32{
+		"NO_VERDICT": 0,
+		"PASS":       1,
+		"WARN":       2,
+		"FAIL":       3,
+	}
+)
+
+func (x QCVerdict) Enum() *QCVerdict {
+	p := new(QCVerdict)
+	*p = x
+	return p
+}
+
+func (x QCVerdict) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QCVerdict) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_archer_proto_enumTypes[0].Descriptor()
+}
+
+func (QCVerdict) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_archer_proto_enumTypes[0]
+}
+
+func (x QCVerdict) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QCVerdict.Descriptor instead.
+func (QCVerdict) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{0}
+}
+
 // State of a sample being handled by Archer.
 type State int32
 
@@ -44,6 +101,8 @@ const (
 	State_ERROR State = 3
 	// sample prep was cancelled via a call to cancel()
 	State_CANCELLED State = 4
+	// sample prep is complete but the sample failed the server QC gate (reads were not uploaded, or were quarantined)
+	State_FAILED_QC State = 5
 )
 
 // Enum value maps for State.
@@ -54,6 +113,7 @@ var (
 		2: "SUCCESS",
 		3: "ERROR",
 		4: "CANCELLED",
+		5: "FAILED_QC",
 	}
 	State_value = map[string]int32{
 		"UNKNOWN":   0,
@@ -61,6 +121,7 @@ var (
 		"SUCCESS":   2,
 		"ERROR":     3,
 		"CANCELLED": 4,
+		"FAILED_QC": 5,
 	}
 )
 
@@ -75,11 +136,11 @@ func (x State) String() string {
 }
 
 func (State) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_v1_archer_proto_enumTypes[0].Descriptor()
+	return file_api_proto_v1_archer_proto_enumTypes[1].Descriptor()
 }
 
 func (State) Type() protoreflect.EnumType {
-	return &file_api_proto_v1_archer_proto_enumTypes[0]
+	return &file_api_proto_v1_archer_proto_enumTypes[1]
 }
 
 func (x State) Number() protoreflect.EnumNumber {
@@ -88,7 +149,337 @@ func (x State) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use State.Descriptor instead.
 func (State) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{0}
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{1}
+}
+
+// EventType describes why a WatchResponse was sent.
+type EventType int32
+
+const (
+	// samples are the current state of the Archer db, sent when a watch starts
+	EventType_SNAPSHOT EventType = 0
+	// a sample has been added to the processing queue
+	EventType_QUEUED EventType = 1
+	// a sample has moved to a new state
+	EventType_STATE_CHANGE EventType = 2
+	// a running sample has made progress
+	EventType_PROGRESS EventType = 3
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "SNAPSHOT",
+		1: "QUEUED",
+		2: "STATE_CHANGE",
+		3: "PROGRESS",
+	}
+	EventType_value = map[string]int32{
+		"SNAPSHOT":     0,
+		"QUEUED":       1,
+		"STATE_CHANGE": 2,
+		"PROGRESS":     3,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_archer_proto_enumTypes[2].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_archer_proto_enumTypes[2]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{2}
+}
+
+// Scoring is how reads are scored against amplicons.
+type Scoring int32
+
+const (
+	// the Jaccard similarity of the read and amplicon sketches (penalises partial reads)
+	Scoring_JACCARD Scoring = 0
+	// the fraction of the read k-mers contained in the amplicon (suits short or truncated reads)
+	Scoring_CONTAINMENT Scoring = 1
+)
+
+// Enum value maps for Scoring.
+var (
+	Scoring_name = map[int32]string{
+		0: "JACCARD",
+		1: "CONTAINMENT",
+	}
+	Scoring_value = map[string]int32{
+		"JACCARD":     0,
+		"CONTAINMENT": 1,
+	}
+)
+
+func (x Scoring) Enum() *Scoring {
+	p := new(Scoring)
+	*p = x
+	return p
+}
+
+func (x Scoring) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Scoring) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_archer_proto_enumTypes[3].Descriptor()
+}
+
+func (Scoring) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_archer_proto_enumTypes[3]
+}
+
+func (x Scoring) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Scoring.Descriptor instead.
+func (Scoring) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{3}
+}
+
+// KmerStrand sets which k-mers are hashed when sketching.
+type KmerStrand int32
+
+const (
+	// use the server setting
+	KmerStrand_DEFAULT_STRAND KmerStrand = 0
+	// hash canonical k-mers, so reads match amplicons on either strand
+	KmerStrand_CANONICAL KmerStrand = 1
+	// hash the k-mers as they are
+	KmerStrand_FORWARD KmerStrand = 2
+)
+
+// Enum value maps for KmerStrand.
+var (
+	KmerStrand_name = map[int32]string{
+		0: "DEFAULT_STRAND",
+		1: "CANONICAL",
+		2: "FORWARD",
+	}
+	KmerStrand_value = map[string]int32{
+		"DEFAULT_STRAND": 0,
+		"CANONICAL":      1,
+		"FORWARD":        2,
+	}
+)
+
+func (x KmerStrand) Enum() *KmerStrand {
+	p := new(KmerStrand)
+	*p = x
+	return p
+}
+
+func (x KmerStrand) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KmerStrand) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_archer_proto_enumTypes[4].Descriptor()
+}
+
+func (KmerStrand) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_archer_proto_enumTypes[4]
+}
+
+func (x KmerStrand) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KmerStrand.Descriptor instead.
+func (KmerStrand) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{4}
+}
+
+// PrimerTrim sets how primers are trimmed from kept reads.
+type PrimerTrim int32
+
+const (
+	// use the server setting
+	PrimerTrim_DEFAULT_TRIM PrimerTrim = 0
+	// upload reads with their primers
+	PrimerTrim_NO_TRIM PrimerTrim = 1
+	// mask primer bases in lower case and set their quality to 0, keeping the read length
+	PrimerTrim_SOFT_TRIM PrimerTrim = 2
+	// remove primer bases from the reads
+	PrimerTrim_HARD_TRIM PrimerTrim = 3
+)
+
+// Enum value maps for PrimerTrim.
+var (
+	PrimerTrim_name = map[int32]string{
+		0: "DEFAULT_TRIM",
+		1: "NO_TRIM",
+		2: "SOFT_TRIM",
+		3: "HARD_TRIM",
+	}
+	PrimerTrim_value = map[string]int32{
+		"DEFAULT_TRIM": 0,
+		"NO_TRIM":      1,
+		"SOFT_TRIM":    2,
+		"HARD_TRIM":    3,
+	}
+)
+
+func (x PrimerTrim) Enum() *PrimerTrim {
+	p := new(PrimerTrim)
+	*p = x
+	return p
+}
+
+func (x PrimerTrim) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PrimerTrim) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_archer_proto_enumTypes[5].Descriptor()
+}
+
+func (PrimerTrim) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_archer_proto_enumTypes[5]
+}
+
+func (x PrimerTrim) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PrimerTrim.Descriptor instead.
+func (PrimerTrim) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{5}
+}
+
+// MultiHitAction sets which reads matching more than one amplicon are discarded.
+type MultiHitAction int32
+
+const (
+	// use the server setting
+	MultiHitAction_DEFAULT_MULTI_HIT MultiHitAction = 0
+	// keep all reads, only counting the ambiguous and chimeric reads
+	MultiHitAction_KEEP_MULTI_HIT MultiHitAction = 1
+	// discard reads matching amplicons that don't overlap (chimeric reads)
+	MultiHitAction_DISCARD_CHIMERIC MultiHitAction = 2
+	// discard reads matching overlapping amplicons (ambiguous reads) and chimeric reads
+	MultiHitAction_DISCARD_MULTI_HIT MultiHitAction = 3
+)
+
+// Enum value maps for MultiHitAction.
+var (
+	MultiHitAction_name = map[int32]string{
+		0: "DEFAULT_MULTI_HIT",
+		1: "KEEP_MULTI_HIT",
+		2: "DISCARD_CHIMERIC",
+		3: "DISCARD_MULTI_HIT",
+	}
+	MultiHitAction_value = map[string]int32{
+		"DEFAULT_MULTI_HIT": 0,
+		"KEEP_MULTI_HIT":    1,
+		"DISCARD_CHIMERIC":  2,
+		"DISCARD_MULTI_HIT": 3,
+	}
+)
+
+func (x MultiHitAction) Enum() *MultiHitAction {
+	p := new(MultiHitAction)
+	*p = x
+	return p
+}
+
+func (x MultiHitAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MultiHitAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_archer_proto_enumTypes[6].Descriptor()
+}
+
+func (MultiHitAction) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_archer_proto_enumTypes[6]
+}
+
+func (x MultiHitAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MultiHitAction.Descriptor instead.
+func (MultiHitAction) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{6}
+}
+
+// HeaderPolicy sets how read headers are written for kept reads.
+type HeaderPolicy int32
+
+const (
+	// use the server setting
+	HeaderPolicy_DEFAULT_HEADERS HeaderPolicy = 0
+	// keep the original read headers
+	HeaderPolicy_KEEP_HEADERS HeaderPolicy = 1
+	// replace the read headers with the read index (1, 2, 3...)
+	HeaderPolicy_INDEX_HEADERS HeaderPolicy = 2
+	// replace the read headers with the sample ID and read index (<sampleID>_1, <sampleID>_2...)
+	HeaderPolicy_SAMPLE_HEADERS HeaderPolicy = 3
+)
+
+// Enum value maps for HeaderPolicy.
+var (
+	HeaderPolicy_name = map[int32]string{
+		0: "DEFAULT_HEADERS",
+		1: "KEEP_HEADERS",
+		2: "INDEX_HEADERS",
+		3: "SAMPLE_HEADERS",
+	}
+	HeaderPolicy_value = map[string]int32{
+		"DEFAULT_HEADERS": 0,
+		"KEEP_HEADERS":    1,
+		"INDEX_HEADERS":   2,
+		"SAMPLE_HEADERS":  3,
+	}
+)
+
+func (x HeaderPolicy) Enum() *HeaderPolicy {
+	p := new(HeaderPolicy)
+	*p = x
+	return p
+}
+
+func (x HeaderPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HeaderPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_archer_proto_enumTypes[7].Descriptor()
+}
+
+func (HeaderPolicy) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_archer_proto_enumTypes[7]
+}
+
+func (x HeaderPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HeaderPolicy.Descriptor instead.
+func (HeaderPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{7}
 }
 
 // SampleStats stores basic numbers from the
@@ -102,7 +493,7 @@ type SampleStats struct {
 	TotalReads int32 `protobuf:"varint,1,opt,name=totalReads,proto3" json:"totalReads,omitempty"`
 	// kept reads across all FASTQs for a sample
 	KeptReads int32 `protobuf:"varint,2,opt,name=keptReads,proto3" json:"keptReads,omitempty"`
-	// ampliconCoverage counts the numer of reads assigned to each amplicon for a sample
+	// ampliconCoverage counts the numer of reads assigned to each amplicon for a sample (before any depth cap)
 	AmpliconCoverage map[string]int32 `protobuf:"bytes,3,rep,name=ampliconCoverage,proto3" json:"ampliconCoverage,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 	// meanAmpliconSize is the mean size of the reference amplicons (incl. primers)
 	MeanAmpliconSize int32 `protobuf:"varint,4,opt,name=meanAmpliconSize,proto3" json:"meanAmpliconSize,omitempty"`
@@ -110,6 +501,56 @@ type SampleStats struct {
 	LengthMax int32 `protobuf:"varint,5,opt,name=lengthMax,proto3" json:"lengthMax,omitempty"`
 	// minLength is the minimum length allowed for a read to be kept.
 	LengthMin int32 `protobuf:"varint,6,opt,name=lengthMin,proto3" json:"lengthMin,omitempty"`
+	// scoring is how reads were scored against amplicons.
+	Scoring Scoring `protobuf:"varint,7,opt,name=scoring,proto3,enum=v1.Scoring" json:"scoring,omitempty"`
+	// scoreThreshold is the minimum score for a read to be kept.
+	ScoreThreshold float32 `protobuf:"fixed32,8,opt,name=scoreThreshold,proto3" json:"scoreThreshold,omitempty"`
+	// kmerSize used to sketch the reads and amplicons.
+	KmerSize int32 `protobuf:"varint,9,opt,name=kmerSize,proto3" json:"kmerSize,omitempty"`
+	// sketchSize used to sketch the reads and amplicons.
+	SketchSize int32 `protobuf:"varint,10,opt,name=sketchSize,proto3" json:"sketchSize,omitempty"`
+	// canonical is true if canonical k-mers were hashed.
+	Canonical bool `protobuf:"varint,11,opt,name=canonical,proto3" json:"canonical,omitempty"`
+	// minMeanQuality is the minimum mean base quality (Phred) for a read to be kept.
+	MinMeanQuality float32 `protobuf:"fixed32,12,opt,name=minMeanQuality,proto3" json:"minMeanQuality,omitempty"`
+	// trimWindow is the sliding window size used for quality trimming (0 if reads weren't trimmed).
+	TrimWindow int32 `protobuf:"varint,13,opt,name=trimWindow,proto3" json:"trimWindow,omitempty"`
+	// trimQuality is the mean base quality (Phred) a window must reach to avoid trimming.
+	TrimQuality float32 `protobuf:"fixed32,14,opt,name=trimQuality,proto3" json:"trimQuality,omitempty"`
+	// trimmedReads is the number of reads shortened by quality trimming.
+	TrimmedReads int32 `protobuf:"varint,15,opt,name=trimmedReads,proto3" json:"trimmedReads,omitempty"`
+	// droppedReads counts the reads that were not kept, by reason.
+	DroppedReads *DroppedReads `protobuf:"bytes,16,opt,name=droppedReads,proto3" json:"droppedReads,omitempty"`
+	// qualityHistogram counts all reads by mean base quality (index is the Phred score, rounded to the nearest integer, with the last bin holding all higher scores).
+	QualityHistogram []int32 `protobuf:"varint,17,rep,packed,name=qualityHistogram,proto3" json:"qualityHistogram,omitempty"`
+	// keptQualityHistogram counts the kept reads by mean base quality, after any trimming.
+	KeptQualityHistogram []int32 `protobuf:"varint,18,rep,packed,name=keptQualityHistogram,proto3" json:"keptQualityHistogram,omitempty"`
+	// primerTrim is how primers were trimmed from the kept reads.
+	PrimerTrim PrimerTrim `protobuf:"varint,19,opt,name=primerTrim,proto3,enum=v1.PrimerTrim" json:"primerTrim,omitempty"`
+	// primerTrimStats counts the primers trimmed from the kept reads.
+	PrimerTrimStats *PrimerTrimStats `protobuf:"bytes,20,opt,name=primerTrimStats,proto3" json:"primerTrimStats,omitempty"`
+	// maxAmpliconDepth is the maximum number of reads kept for each amplicon (0 if there was no cap).
+	MaxAmpliconDepth int32 `protobuf:"varint,21,opt,name=maxAmpliconDepth,proto3" json:"maxAmpliconDepth,omitempty"`
+	// normalisedCoverage counts the number of reads kept for each amplicon, after any depth cap.
+	NormalisedCoverage map[string]int32 `protobuf:"bytes,22,rep,name=normalisedCoverage,proto3" json:"normalisedCoverage,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// hostThreshold is the fraction of sampled k-mers in the host index for a read to be removed (0 if there was no host screen).
+	HostThreshold float32 `protobuf:"fixed32,23,opt,name=hostThreshold,proto3" json:"hostThreshold,omitempty"`
+	// headerPolicy is how the read headers were written for the kept reads.
+	HeaderPolicy HeaderPolicy `protobuf:"varint,24,opt,name=headerPolicy,proto3,enum=v1.HeaderPolicy" json:"headerPolicy,omitempty"`
+	// headerMap is the local file mapping the new read headers to the original headers (empty if there is no mapping).
+	HeaderMap string `protobuf:"bytes,25,opt,name=headerMap,proto3" json:"headerMap,omitempty"`
+	// multiHitRatio is the fraction of the best amplicon score that a second amplicon must reach for a read to be flagged as ambiguous or chimeric.
+	MultiHitRatio float32 `protobuf:"fixed32,26,opt,name=multiHitRatio,proto3" json:"multiHitRatio,omitempty"`
+	// multiHitAction is which flagged reads were discarded.
+	MultiHitAction MultiHitAction `protobuf:"varint,27,opt,name=multiHitAction,proto3,enum=v1.MultiHitAction" json:"multiHitAction,omitempty"`
+	// ambiguousReads is the number of reads matching two overlapping amplicons almost equally.
+	AmbiguousReads int32 `protobuf:"varint,28,opt,name=ambiguousReads,proto3" json:"ambiguousReads,omitempty"`
+	// chimericReads is the number of reads matching two amplicons that don't overlap almost equally.
+	ChimericReads int32 `protobuf:"varint,29,opt,name=chimericReads,proto3" json:"chimericReads,omitempty"`
+	// poolStats summarises the coverage of each primer pool (from the primer BED), keyed by pool name.
+	PoolStats map[string]*PoolStats `protobuf:"bytes,30,rep,name=poolStats,proto3" json:"poolStats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// referenceStats summarises the coverage of each reference sequence in the scheme (e.g. each genome segment), keyed by reference.
+	ReferenceStats map[string]*ReferenceStats `protobuf:"bytes,31,rep,name=referenceStats,proto3" json:"referenceStats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *SampleStats) Reset() {
@@ -186,148 +627,310 @@ func (x *SampleStats) GetLengthMin() int32 {
 	return 0
 }
 
-// SampleInfo describes how a sample was
-// processed by Archer.
-type SampleInfo struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// sampleID is the sample identifier - as returned by Process()
-	SampleID string `protobuf:"bytes,1,opt,name=sampleID,proto3" json:"sampleID,omitempty"`
-	// the original message used to start the sample processing
-	ProcessRequest *ProcessRequest `protobuf:"bytes,2,opt,name=processRequest,proto3" json:"processRequest,omitempty"`
-	// state the sample is in
-	State State `protobuf:"varint,3,opt,name=state,proto3,enum=v1.State" json:"state,omitempty"`
-	// errors will contain encountered errors (if state is STATE_ERROR, otherwise this will be empty)
-	Errors []string `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
-	// filesDiscovered is the number of files found for this sample
-	FilesDiscovered int32 `protobuf:"varint,5,opt,name=filesDiscovered,proto3" json:"filesDiscovered,omitempty"`
-	// startTime for processing
-	StartTime *timestamp.Timestamp `protobuf:"bytes,6,opt,name=startTime,proto3" json:"startTime,omitempty"`
-	// endTime for processing (unset if processing still running)
-	EndTime *timestamp.Timestamp `protobuf:"bytes,7,opt,name=endTime,proto3" json:"endTime,omitempty"`
-	// processStats contains details on the processing request output
-	ProcessStats *SampleStats `protobuf:"bytes,8,opt,name=processStats,proto3" json:"processStats,omitempty"`
-	// endpoint is the AWS S3 location for the processed sample
-	Endpoint string `protobuf:"bytes,9,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+func (x *SampleStats) GetScoring() Scoring {
+	if x != nil {
+		return x.Scoring
+	}
+	return Scoring_JACCARD
 }
 
-func (x *SampleInfo) Reset() {
-	*x = SampleInfo{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_v1_archer_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *SampleStats) GetScoreThreshold() float32 {
+	if x != nil {
+		return x.ScoreThreshold
 	}
+	return 0
 }
 
-func (x *SampleInfo) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SampleStats) GetKmerSize() int32 {
+	if x != nil {
+		return x.KmerSize
+	}
+	return 0
 }
 
-func (*SampleInfo) ProtoMessage() {}
-
-func (x *SampleInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_archer_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *SampleStats) GetSketchSize() int32 {
+	if x != nil {
+		return x.SketchSize
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use SampleInfo.ProtoReflect.Descriptor instead.
-func (*SampleInfo) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{1}
+func (x *SampleStats) GetCanonical() bool {
+	if x != nil {
+		return x.Canonical
+	}
+	return false
 }
 
-func (x *SampleInfo) GetSampleID() string {
+func (x *SampleStats) GetMinMeanQuality() float32 {
 	if x != nil {
-		return x.SampleID
+		return x.MinMeanQuality
 	}
-	return ""
+	return 0
 }
 
-func (x *SampleInfo) GetProcessRequest() *ProcessRequest {
+func (x *SampleStats) GetTrimWindow() int32 {
 	if x != nil {
-		return x.ProcessRequest
+		return x.TrimWindow
 	}
-	return nil
+	return 0
 }
 
-func (x *SampleInfo) GetState() State {
+func (x *SampleStats) GetTrimQuality() float32 {
 	if x != nil {
-		return x.State
+		return x.TrimQuality
 	}
-	return State_UNKNOWN
+	return 0
 }
 
-func (x *SampleInfo) GetErrors() []string {
+func (x *SampleStats) GetTrimmedReads() int32 {
 	if x != nil {
-		return x.Errors
+		return x.TrimmedReads
+	}
+	return 0
+}
+
+func (x *SampleStats) GetDroppedReads() *DroppedReads {
+	if x != nil {
+		return x.DroppedReads
 	}
 	return nil
 }
 
-func (x *SampleInfo) GetFilesDiscovered() int32 {
+func (x *SampleStats) GetQualityHistogram() []int32 {
 	if x != nil {
-		return x.FilesDiscovered
+		return x.QualityHistogram
 	}
-	return 0
+	return nil
 }
 
-func (x *SampleInfo) GetStartTime() *timestamp.Timestamp {
+func (x *SampleStats) GetKeptQualityHistogram() []int32 {
 	if x != nil {
-		return x.StartTime
+		return x.KeptQualityHistogram
 	}
 	return nil
 }
 
-func (x *SampleInfo) GetEndTime() *timestamp.Timestamp {
+func (x *SampleStats) GetPrimerTrim() PrimerTrim {
 	if x != nil {
-		return x.EndTime
+		return x.PrimerTrim
+	}
+	return PrimerTrim_DEFAULT_TRIM
+}
+
+func (x *SampleStats) GetPrimerTrimStats() *PrimerTrimStats {
+	if x != nil {
+		return x.PrimerTrimStats
 	}
 	return nil
 }
 
-func (x *SampleInfo) GetProcessStats() *SampleStats {
+func (x *SampleStats) GetMaxAmpliconDepth() int32 {
 	if x != nil {
-		return x.ProcessStats
+		return x.MaxAmpliconDepth
+	}
+	return 0
+}
+
+func (x *SampleStats) GetNormalisedCoverage() map[string]int32 {
+	if x != nil {
+		return x.NormalisedCoverage
 	}
 	return nil
 }
 
-func (x *SampleInfo) GetEndpoint() string {
+func (x *SampleStats) GetHostThreshold() float32 {
 	if x != nil {
-		return x.Endpoint
+		return x.HostThreshold
+	}
+	return 0
+}
+
+func (x *SampleStats) GetHeaderPolicy() HeaderPolicy {
+	if x != nil {
+		return x.HeaderPolicy
+	}
+	return HeaderPolicy_DEFAULT_HEADERS
+}
+
+func (x *SampleStats) GetHeaderMap() string {
+	if x != nil {
+		return x.HeaderMap
 	}
 	return ""
 }
 
-// ProcessRequest will request a sample to be processed by Archer.
-type ProcessRequest struct {
+func (x *SampleStats) GetMultiHitRatio() float32 {
+	if x != nil {
+		return x.MultiHitRatio
+	}
+	return 0
+}
+
+func (x *SampleStats) GetMultiHitAction() MultiHitAction {
+	if x != nil {
+		return x.MultiHitAction
+	}
+	return MultiHitAction_DEFAULT_MULTI_HIT
+}
+
+func (x *SampleStats) GetAmbiguousReads() int32 {
+	if x != nil {
+		return x.AmbiguousReads
+	}
+	return 0
+}
+
+func (x *SampleStats) GetChimericReads() int32 {
+	if x != nil {
+		return x.ChimericReads
+	}
+	return 0
+}
+
+func (x *SampleStats) GetPoolStats() map[string]*PoolStats {
+	if x != nil {
+		return x.PoolStats
+	}
+	return nil
+}
+
+func (x *SampleStats) GetReferenceStats() map[string]*ReferenceStats {
+	if x != nil {
+		return x.ReferenceStats
+	}
+	return nil
+}
+
+// ReferenceStats summarises the reads assigned
+// to the amplicons on a reference sequence for
+// a sample.
+type ReferenceStats struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// api version
-	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
-	// sampleID is the sample identifier - users job to assign this and make it unique
-	SampleID string `protobuf:"bytes,2,opt,name=sampleID,proto3" json:"sampleID,omitempty"`
-	// inputFASTQfiles for this sample
-	InputFASTQfiles []string `protobuf:"bytes,3,rep,name=inputFASTQfiles,proto3" json:"inputFASTQfiles,omitempty"`
-	// scheme denotes the amplicon scheme used for the sample
-	Scheme string `protobuf:"bytes,4,opt,name=scheme,proto3" json:"scheme,omitempty"`
-	// schemeVersion denotes the amplicon scheme version used
-	SchemeVersion int32 `protobuf:"varint,5,opt,name=schemeVersion,proto3" json:"schemeVersion,omitempty"`
+	// amplicons is the number of amplicons on the reference.
+	Amplicons int32 `protobuf:"varint,1,opt,name=amplicons,proto3" json:"amplicons,omitempty"`
+	// coveredAmplicons is the number of amplicons on the reference with at least one kept read.
+	CoveredAmplicons int32 `protobuf:"varint,2,opt,name=coveredAmplicons,proto3" json:"coveredAmplicons,omitempty"`
+	// reads is the number of reads assigned to amplicons on the reference (before any depth cap).
+	Reads int32 `protobuf:"varint,3,opt,name=reads,proto3" json:"reads,omitempty"`
+	// normalisedReads is the number of reads kept for amplicons on the reference, after any depth cap.
+	NormalisedReads int32 `protobuf:"varint,4,opt,name=normalisedReads,proto3" json:"normalisedReads,omitempty"`
+	// length is the length of the reference sequence.
+	Length int32 `protobuf:"varint,5,opt,name=length,proto3" json:"length,omitempty"`
+	// referenceCoverage is the estimated percentage of the reference covered by amplicons with at least one kept read (incl. primers).
+	ReferenceCoverage float32 `protobuf:"fixed32,6,opt,name=referenceCoverage,proto3" json:"referenceCoverage,omitempty"`
+}
+
+func (x *ReferenceStats) Reset() {
+	*x = ReferenceStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *ProcessRequest) Reset() {
-	*x = ProcessRequest{}
+func (x *ReferenceStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReferenceStats) ProtoMessage() {}
+
+func (x *ReferenceStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReferenceStats.ProtoReflect.Descriptor instead.
+func (*ReferenceStats) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReferenceStats) GetAmplicons() int32 {
+	if x != nil {
+		return x.Amplicons
+	}
+	return 0
+}
+
+func (x *ReferenceStats) GetCoveredAmplicons() int32 {
+	if x != nil {
+		return x.CoveredAmplicons
+	}
+	return 0
+}
+
+func (x *ReferenceStats) GetReads() int32 {
+	if x != nil {
+		return x.Reads
+	}
+	return 0
+}
+
+func (x *ReferenceStats) GetNormalisedReads() int32 {
+	if x != nil {
+		return x.NormalisedReads
+	}
+	return 0
+}
+
+func (x *ReferenceStats) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *ReferenceStats) GetReferenceCoverage() float32 {
+	if x != nil {
+		return x.ReferenceCoverage
+	}
+	return 0
+}
+
+// SampleQC reports the amplicon dropouts and
+// estimated reference coverage for a sample.
+type SampleQC struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// verdict is the overall QC result.
+	Verdict QCVerdict `protobuf:"varint,1,opt,name=verdict,proto3,enum=v1.QCVerdict" json:"verdict,omitempty"`
+	// minDepth is the number of kept reads an amplicon needed to pass.
+	MinDepth int32 `protobuf:"varint,2,opt,name=minDepth,proto3" json:"minDepth,omitempty"`
+	// passedAmplicons is the number of amplicons with at least minDepth kept reads.
+	PassedAmplicons int32 `protobuf:"varint,3,opt,name=passedAmplicons,proto3" json:"passedAmplicons,omitempty"`
+	// totalAmplicons is the number of amplicons in the scheme.
+	TotalAmplicons int32 `protobuf:"varint,4,opt,name=totalAmplicons,proto3" json:"totalAmplicons,omitempty"`
+	// droppedAmplicons are the names of the amplicons below minDepth.
+	DroppedAmplicons []string `protobuf:"bytes,5,rep,name=droppedAmplicons,proto3" json:"droppedAmplicons,omitempty"`
+	// referenceCoverage is the estimated percentage of the reference covered by the passing amplicons (incl. primers).
+	ReferenceCoverage float32 `protobuf:"fixed32,6,opt,name=referenceCoverage,proto3" json:"referenceCoverage,omitempty"`
+	// warnCoverage is the reference coverage (percentage) below which the sample was given a WARN.
+	WarnCoverage float32 `protobuf:"fixed32,7,opt,name=warnCoverage,proto3" json:"warnCoverage,omitempty"`
+	// failCoverage is the reference coverage (percentage) below which the sample was given a FAIL.
+	FailCoverage float32 `protobuf:"fixed32,8,opt,name=failCoverage,proto3" json:"failCoverage,omitempty"`
+	// offTarget is the fraction of reads passing the length and quality filters that were not kept as amplicon reads (no hit or host).
+	OffTarget float32 `protobuf:"fixed32,9,opt,name=offTarget,proto3" json:"offTarget,omitempty"`
+	// gateFailures are the reasons the sample failed the server QC gate (empty if it passed or there is no gate).
+	GateFailures []string `protobuf:"bytes,10,rep,name=gateFailures,proto3" json:"gateFailures,omitempty"`
+	// quarantined is true if the reads for a sample that failed the QC gate were uploaded to the quarantine prefix.
+	Quarantined bool `protobuf:"varint,11,opt,name=quarantined,proto3" json:"quarantined,omitempty"`
+}
+
+func (x *SampleQC) Reset() {
+	*x = SampleQC{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_proto_v1_archer_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -335,13 +938,13 @@ func (x *ProcessRequest) Reset() {
 	}
 }
 
-func (x *ProcessRequest) String() string {
+func (x *SampleQC) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProcessRequest) ProtoMessage() {}
+func (*SampleQC) ProtoMessage() {}
 
-func (x *ProcessRequest) ProtoReflect() protoreflect.Message {
+func (x *SampleQC) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_v1_archer_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -353,60 +956,111 @@ func (x *ProcessRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProcessRequest.ProtoReflect.Descriptor instead.
-func (*ProcessRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SampleQC.ProtoReflect.Descriptor instead.
+func (*SampleQC) Descriptor() ([]byte, []int) {
 	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *ProcessRequest) GetApiVersion() string {
+func (x *SampleQC) GetVerdict() QCVerdict {
 	if x != nil {
-		return x.ApiVersion
+		return x.Verdict
 	}
-	return ""
+	return QCVerdict_NO_VERDICT
 }
 
-func (x *ProcessRequest) GetSampleID() string {
+func (x *SampleQC) GetMinDepth() int32 {
 	if x != nil {
-		return x.SampleID
+		return x.MinDepth
 	}
-	return ""
+	return 0
 }
 
-func (x *ProcessRequest) GetInputFASTQfiles() []string {
+func (x *SampleQC) GetPassedAmplicons() int32 {
 	if x != nil {
-		return x.InputFASTQfiles
+		return x.PassedAmplicons
+	}
+	return 0
+}
+
+func (x *SampleQC) GetTotalAmplicons() int32 {
+	if x != nil {
+		return x.TotalAmplicons
+	}
+	return 0
+}
+
+func (x *SampleQC) GetDroppedAmplicons() []string {
+	if x != nil {
+		return x.DroppedAmplicons
 	}
 	return nil
 }
 
-func (x *ProcessRequest) GetScheme() string {
+func (x *SampleQC) GetReferenceCoverage() float32 {
 	if x != nil {
-		return x.Scheme
+		return x.ReferenceCoverage
 	}
-	return ""
+	return 0
 }
 
-func (x *ProcessRequest) GetSchemeVersion() int32 {
+func (x *SampleQC) GetWarnCoverage() float32 {
 	if x != nil {
-		return x.SchemeVersion
+		return x.WarnCoverage
 	}
 	return 0
 }
 
-// ProcessResponse
-type ProcessResponse struct {
+func (x *SampleQC) GetFailCoverage() float32 {
+	if x != nil {
+		return x.FailCoverage
+	}
+	return 0
+}
+
+func (x *SampleQC) GetOffTarget() float32 {
+	if x != nil {
+		return x.OffTarget
+	}
+	return 0
+}
+
+func (x *SampleQC) GetGateFailures() []string {
+	if x != nil {
+		return x.GateFailures
+	}
+	return nil
+}
+
+func (x *SampleQC) GetQuarantined() bool {
+	if x != nil {
+		return x.Quarantined
+	}
+	return false
+}
+
+// PoolStats summarises the reads assigned to
+// the amplicons in a primer pool for a sample.
+type PoolStats struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// api version
-	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
-	// identifier for the sample that processing was started for (used to monitor or cancel the sample)
-	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-}
-
-func (x *ProcessResponse) Reset() {
-	*x = ProcessResponse{}
+	// amplicons is the number of amplicons in the pool.
+	Amplicons int32 `protobuf:"varint,1,opt,name=amplicons,proto3" json:"amplicons,omitempty"`
+	// coveredAmplicons is the number of amplicons in the pool with at least one read assigned.
+	CoveredAmplicons int32 `protobuf:"varint,2,opt,name=coveredAmplicons,proto3" json:"coveredAmplicons,omitempty"`
+	// reads is the number of reads assigned to amplicons in the pool (before any depth cap).
+	Reads int32 `protobuf:"varint,3,opt,name=reads,proto3" json:"reads,omitempty"`
+	// normalisedReads is the number of reads kept for amplicons in the pool, after any depth cap.
+	NormalisedReads int32 `protobuf:"varint,4,opt,name=normalisedReads,proto3" json:"normalisedReads,omitempty"`
+	// meanCoverage is the mean number of reads assigned to each amplicon in the pool.
+	MeanCoverage float32 `protobuf:"fixed32,5,opt,name=meanCoverage,proto3" json:"meanCoverage,omitempty"`
+	// relativeCoverage is the mean coverage of the pool as a fraction of the best covered pool.
+	RelativeCoverage float32 `protobuf:"fixed32,6,opt,name=relativeCoverage,proto3" json:"relativeCoverage,omitempty"`
+}
+
+func (x *PoolStats) Reset() {
+	*x = PoolStats{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_proto_v1_archer_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -414,72 +1068,1024 @@ func (x *ProcessResponse) Reset() {
 	}
 }
 
-func (x *ProcessResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *PoolStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolStats) ProtoMessage() {}
+
+func (x *PoolStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolStats.ProtoReflect.Descriptor instead.
+func (*PoolStats) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PoolStats) GetAmplicons() int32 {
+	if x != nil {
+		return x.Amplicons
+	}
+	return 0
+}
+
+func (x *PoolStats) GetCoveredAmplicons() int32 {
+	if x != nil {
+		return x.CoveredAmplicons
+	}
+	return 0
+}
+
+func (x *PoolStats) GetReads() int32 {
+	if x != nil {
+		return x.Reads
+	}
+	return 0
+}
+
+func (x *PoolStats) GetNormalisedReads() int32 {
+	if x != nil {
+		return x.NormalisedReads
+	}
+	return 0
+}
+
+func (x *PoolStats) GetMeanCoverage() float32 {
+	if x != nil {
+		return x.MeanCoverage
+	}
+	return 0
+}
+
+func (x *PoolStats) GetRelativeCoverage() float32 {
+	if x != nil {
+		return x.RelativeCoverage
+	}
+	return 0
+}
+
+// PrimerTrimStats counts the primers trimmed
+// from the kept reads for a sample.
+type PrimerTrimStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// trimmedReads is the number of kept reads with at least one primer trimmed.
+	TrimmedReads int32 `protobuf:"varint,1,opt,name=trimmedReads,proto3" json:"trimmedReads,omitempty"`
+	// startPrimers is the number of kept reads with a primer found at the start of the read.
+	StartPrimers int32 `protobuf:"varint,2,opt,name=startPrimers,proto3" json:"startPrimers,omitempty"`
+	// endPrimers is the number of kept reads with a primer found at the end of the read.
+	EndPrimers int32 `protobuf:"varint,3,opt,name=endPrimers,proto3" json:"endPrimers,omitempty"`
+	// trimmedBases is the number of bases trimmed (or masked) from the kept reads.
+	TrimmedBases int64 `protobuf:"varint,4,opt,name=trimmedBases,proto3" json:"trimmedBases,omitempty"`
+}
+
+func (x *PrimerTrimStats) Reset() {
+	*x = PrimerTrimStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrimerTrimStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrimerTrimStats) ProtoMessage() {}
+
+func (x *PrimerTrimStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrimerTrimStats.ProtoReflect.Descriptor instead.
+func (*PrimerTrimStats) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PrimerTrimStats) GetTrimmedReads() int32 {
+	if x != nil {
+		return x.TrimmedReads
+	}
+	return 0
+}
+
+func (x *PrimerTrimStats) GetStartPrimers() int32 {
+	if x != nil {
+		return x.StartPrimers
+	}
+	return 0
+}
+
+func (x *PrimerTrimStats) GetEndPrimers() int32 {
+	if x != nil {
+		return x.EndPrimers
+	}
+	return 0
+}
+
+func (x *PrimerTrimStats) GetTrimmedBases() int64 {
+	if x != nil {
+		return x.TrimmedBases
+	}
+	return 0
+}
+
+// DroppedReads counts the reads that were not
+// kept for a sample, for each filter.
+type DroppedReads struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// length is the number of reads outside the length bounds.
+	Length int32 `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	// quality is the number of reads below the minimum mean base quality.
+	Quality int32 `protobuf:"varint,2,opt,name=quality,proto3" json:"quality,omitempty"`
+	// noHit is the number of reads that didn't match an amplicon well enough.
+	NoHit int32 `protobuf:"varint,3,opt,name=noHit,proto3" json:"noHit,omitempty"`
+	// depthCap is the number of reads assigned to an amplicon that had already reached the maximum depth.
+	DepthCap int32 `protobuf:"varint,4,opt,name=depthCap,proto3" json:"depthCap,omitempty"`
+	// host is the number of reads assigned to an amplicon that matched the host index and were removed.
+	Host int32 `protobuf:"varint,5,opt,name=host,proto3" json:"host,omitempty"`
+	// multiHit is the number of ambiguous or chimeric reads that were discarded.
+	MultiHit int32 `protobuf:"varint,6,opt,name=multiHit,proto3" json:"multiHit,omitempty"`
+}
+
+func (x *DroppedReads) Reset() {
+	*x = DroppedReads{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DroppedReads) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DroppedReads) ProtoMessage() {}
+
+func (x *DroppedReads) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DroppedReads.ProtoReflect.Descriptor instead.
+func (*DroppedReads) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DroppedReads) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *DroppedReads) GetQuality() int32 {
+	if x != nil {
+		return x.Quality
+	}
+	return 0
+}
+
+func (x *DroppedReads) GetNoHit() int32 {
+	if x != nil {
+		return x.NoHit
+	}
+	return 0
+}
+
+func (x *DroppedReads) GetDepthCap() int32 {
+	if x != nil {
+		return x.DepthCap
+	}
+	return 0
+}
+
+func (x *DroppedReads) GetHost() int32 {
+	if x != nil {
+		return x.Host
+	}
+	return 0
+}
+
+func (x *DroppedReads) GetMultiHit() int32 {
+	if x != nil {
+		return x.MultiHit
+	}
+	return 0
+}
+
+// SampleProgress is a snapshot of a running sample.
+type SampleProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// totalReads processed so far
+	TotalReads int32 `protobuf:"varint,1,opt,name=totalReads,proto3" json:"totalReads,omitempty"`
+	// keptReads so far
+	KeptReads int32 `protobuf:"varint,2,opt,name=keptReads,proto3" json:"keptReads,omitempty"`
+	// currentFile is the index of the input FASTQ file being processed
+	CurrentFile int32 `protobuf:"varint,3,opt,name=currentFile,proto3" json:"currentFile,omitempty"`
+	// bytesUploaded is the number of compressed bytes sent to the endpoint so far
+	BytesUploaded int64 `protobuf:"varint,4,opt,name=bytesUploaded,proto3" json:"bytesUploaded,omitempty"`
+}
+
+func (x *SampleProgress) Reset() {
+	*x = SampleProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SampleProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SampleProgress) ProtoMessage() {}
+
+func (x *SampleProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SampleProgress.ProtoReflect.Descriptor instead.
+func (*SampleProgress) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SampleProgress) GetTotalReads() int32 {
+	if x != nil {
+		return x.TotalReads
+	}
+	return 0
+}
+
+func (x *SampleProgress) GetKeptReads() int32 {
+	if x != nil {
+		return x.KeptReads
+	}
+	return 0
+}
+
+func (x *SampleProgress) GetCurrentFile() int32 {
+	if x != nil {
+		return x.CurrentFile
+	}
+	return 0
+}
+
+func (x *SampleProgress) GetBytesUploaded() int64 {
+	if x != nil {
+		return x.BytesUploaded
+	}
+	return 0
+}
+
+// SampleInfo describes how a sample was
+// processed by Archer.
+type SampleInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// sampleID is the sample identifier - as returned by Process()
+	SampleID string `protobuf:"bytes,1,opt,name=sampleID,proto3" json:"sampleID,omitempty"`
+	// the original message used to start the sample processing
+	ProcessRequest *ProcessRequest `protobuf:"bytes,2,opt,name=processRequest,proto3" json:"processRequest,omitempty"`
+	// state the sample is in
+	State State `protobuf:"varint,3,opt,name=state,proto3,enum=v1.State" json:"state,omitempty"`
+	// errors will contain encountered errors (if state is STATE_ERROR, otherwise this will be empty)
+	Errors []string `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	// filesDiscovered is the number of FASTQ files found for this sample (after expanding any directories or glob patterns)
+	FilesDiscovered int32 `protobuf:"varint,5,opt,name=filesDiscovered,proto3" json:"filesDiscovered,omitempty"`
+	// startTime for processing
+	StartTime *timestamp.Timestamp `protobuf:"bytes,6,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	// endTime for processing (unset if processing still running)
+	EndTime *timestamp.Timestamp `protobuf:"bytes,7,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	// processStats contains details on the processing request output
+	ProcessStats *SampleStats `protobuf:"bytes,8,opt,name=processStats,proto3" json:"processStats,omitempty"`
+	// endpoint is the AWS S3 location for the processed sample
+	Endpoint string `protobuf:"bytes,9,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// retries is the number of times processing was retried after a transient upload error
+	Retries int32 `protobuf:"varint,10,opt,name=retries,proto3" json:"retries,omitempty"`
+	// sampleQC is the amplicon dropout report and QC verdict (unset until the sample is processed)
+	SampleQC *SampleQC `protobuf:"bytes,11,opt,name=sampleQC,proto3" json:"sampleQC,omitempty"`
+}
+
+func (x *SampleInfo) Reset() {
+	*x = SampleInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SampleInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SampleInfo) ProtoMessage() {}
+
+func (x *SampleInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SampleInfo.ProtoReflect.Descriptor instead.
+func (*SampleInfo) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SampleInfo) GetSampleID() string {
+	if x != nil {
+		return x.SampleID
+	}
+	return ""
+}
+
+func (x *SampleInfo) GetProcessRequest() *ProcessRequest {
+	if x != nil {
+		return x.ProcessRequest
+	}
+	return nil
+}
+
+func (x *SampleInfo) GetState() State {
+	if x != nil {
+		return x.State
+	}
+	return State_UNKNOWN
+}
+
+func (x *SampleInfo) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *SampleInfo) GetFilesDiscovered() int32 {
+	if x != nil {
+		return x.FilesDiscovered
+	}
+	return 0
+}
+
+func (x *SampleInfo) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *SampleInfo) GetEndTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *SampleInfo) GetProcessStats() *SampleStats {
+	if x != nil {
+		return x.ProcessStats
+	}
+	return nil
+}
+
+func (x *SampleInfo) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *SampleInfo) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *SampleInfo) GetSampleQC() *SampleQC {
+	if x != nil {
+		return x.SampleQC
+	}
+	return nil
+}
+
+// ProcessRequest will request a sample to be processed by Archer.
+type ProcessRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// api version
+	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	// sampleID is the sample identifier - users job to assign this and make it unique
+	SampleID string `protobuf:"bytes,2,opt,name=sampleID,proto3" json:"sampleID,omitempty"`
+	// inputFASTQfiles for this sample, which can be files (optionally gzip/bgzip compressed), directories or glob patterns
+	// (these are replaced by the files found when the request is accepted)
+	InputFASTQfiles []string `protobuf:"bytes,3,rep,name=inputFASTQfiles,proto3" json:"inputFASTQfiles,omitempty"`
+	// scheme denotes the amplicon scheme used for the sample
+	Scheme string `protobuf:"bytes,4,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	// schemeVersion denotes the amplicon scheme version used
+	SchemeVersion int32 `protobuf:"varint,5,opt,name=schemeVersion,proto3" json:"schemeVersion,omitempty"`
+	// scoring sets how reads are scored against amplicons (default is JACCARD)
+	Scoring Scoring `protobuf:"varint,6,opt,name=scoring,proto3,enum=v1.Scoring" json:"scoring,omitempty"`
+	// scoreThreshold is the minimum score for a read to be kept (0 uses the default for the scoring)
+	ScoreThreshold float32 `protobuf:"fixed32,7,opt,name=scoreThreshold,proto3" json:"scoreThreshold,omitempty"`
+	// kmerSize to sketch reads and amplicons with (0 uses the server setting)
+	KmerSize int32 `protobuf:"varint,8,opt,name=kmerSize,proto3" json:"kmerSize,omitempty"`
+	// sketchSize to sketch reads and amplicons with (0 uses the server setting)
+	SketchSize int32 `protobuf:"varint,9,opt,name=sketchSize,proto3" json:"sketchSize,omitempty"`
+	// kmerStrand sets if canonical k-mers are hashed (DEFAULT_STRAND uses the server setting)
+	KmerStrand KmerStrand `protobuf:"varint,10,opt,name=kmerStrand,proto3,enum=v1.KmerStrand" json:"kmerStrand,omitempty"`
+	// minLength is the minimum read length to keep (0 uses lengthTolerance), which can't be less than the k-mer size
+	MinLength int32 `protobuf:"varint,11,opt,name=minLength,proto3" json:"minLength,omitempty"`
+	// maxLength is the maximum read length to keep (0 uses lengthTolerance)
+	MaxLength int32 `protobuf:"varint,12,opt,name=maxLength,proto3" json:"maxLength,omitempty"`
+	// lengthTolerance is the fraction above/below the mean amplicon size to keep reads within (0 uses the server setting)
+	LengthTolerance float32 `protobuf:"fixed32,13,opt,name=lengthTolerance,proto3" json:"lengthTolerance,omitempty"`
+	// minMeanQuality is the minimum mean base quality (Phred) to keep a read (0 uses the server setting)
+	MinMeanQuality float32 `protobuf:"fixed32,14,opt,name=minMeanQuality,proto3" json:"minMeanQuality,omitempty"`
+	// trimWindow is the window size for sliding-window quality trimming (0 uses the server setting, and a request setting only trimWindow is rejected if the server has no trimQuality)
+	TrimWindow int32 `protobuf:"varint,15,opt,name=trimWindow,proto3" json:"trimWindow,omitempty"`
+	// trimQuality is the mean base quality (Phred) a window must reach, or the read is cut at that window (0 uses the server setting, and a request setting only trimQuality is rejected if the server has no trimWindow)
+	TrimQuality float32 `protobuf:"fixed32,16,opt,name=trimQuality,proto3" json:"trimQuality,omitempty"`
+	// primerTrim sets how primers are trimmed from the kept reads (DEFAULT_TRIM uses the server setting)
+	PrimerTrim PrimerTrim `protobuf:"varint,17,opt,name=primerTrim,proto3,enum=v1.PrimerTrim" json:"primerTrim,omitempty"`
+	// maxAmpliconDepth caps the number of reads kept for each amplicon, keeping the first reads assigned (0 uses the server setting)
+	MaxAmpliconDepth int32 `protobuf:"varint,18,opt,name=maxAmpliconDepth,proto3" json:"maxAmpliconDepth,omitempty"`
+	// headerPolicy sets how read headers are written for the kept reads (DEFAULT_HEADERS uses the server setting)
+	HeaderPolicy HeaderPolicy `protobuf:"varint,19,opt,name=headerPolicy,proto3,enum=v1.HeaderPolicy" json:"headerPolicy,omitempty"`
+	// multiHitRatio is the fraction of the best amplicon score that a second amplicon must reach to flag a read as ambiguous or chimeric (0 uses the server setting)
+	MultiHitRatio float32 `protobuf:"fixed32,20,opt,name=multiHitRatio,proto3" json:"multiHitRatio,omitempty"`
+	// multiHitAction sets which flagged reads are discarded (DEFAULT_MULTI_HIT uses the server setting)
+	MultiHitAction MultiHitAction `protobuf:"varint,21,opt,name=multiHitAction,proto3,enum=v1.MultiHitAction" json:"multiHitAction,omitempty"`
+	// qcMinDepth is the number of kept reads an amplicon needs to pass QC (0 uses the server setting)
+	QcMinDepth int32 `protobuf:"varint,22,opt,name=qcMinDepth,proto3" json:"qcMinDepth,omitempty"`
+}
+
+func (x *ProcessRequest) Reset() {
+	*x = ProcessRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessRequest) ProtoMessage() {}
+
+func (x *ProcessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessRequest.ProtoReflect.Descriptor instead.
+func (*ProcessRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProcessRequest) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *ProcessRequest) GetSampleID() string {
+	if x != nil {
+		return x.SampleID
+	}
+	return ""
+}
+
+func (x *ProcessRequest) GetInputFASTQfiles() []string {
+	if x != nil {
+		return x.InputFASTQfiles
+	}
+	return nil
+}
+
+func (x *ProcessRequest) GetScheme() string {
+	if x != nil {
+		return x.Scheme
+	}
+	return ""
+}
+
+func (x *ProcessRequest) GetSchemeVersion() int32 {
+	if x != nil {
+		return x.SchemeVersion
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetScoring() Scoring {
+	if x != nil {
+		return x.Scoring
+	}
+	return Scoring_JACCARD
+}
+
+func (x *ProcessRequest) GetScoreThreshold() float32 {
+	if x != nil {
+		return x.ScoreThreshold
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetKmerSize() int32 {
+	if x != nil {
+		return x.KmerSize
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetSketchSize() int32 {
+	if x != nil {
+		return x.SketchSize
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetKmerStrand() KmerStrand {
+	if x != nil {
+		return x.KmerStrand
+	}
+	return KmerStrand_DEFAULT_STRAND
+}
+
+func (x *ProcessRequest) GetMinLength() int32 {
+	if x != nil {
+		return x.MinLength
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetMaxLength() int32 {
+	if x != nil {
+		return x.MaxLength
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetLengthTolerance() float32 {
+	if x != nil {
+		return x.LengthTolerance
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetMinMeanQuality() float32 {
+	if x != nil {
+		return x.MinMeanQuality
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetTrimWindow() int32 {
+	if x != nil {
+		return x.TrimWindow
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetTrimQuality() float32 {
+	if x != nil {
+		return x.TrimQuality
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetPrimerTrim() PrimerTrim {
+	if x != nil {
+		return x.PrimerTrim
+	}
+	return PrimerTrim_DEFAULT_TRIM
+}
+
+func (x *ProcessRequest) GetMaxAmpliconDepth() int32 {
+	if x != nil {
+		return x.MaxAmpliconDepth
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetHeaderPolicy() HeaderPolicy {
+	if x != nil {
+		return x.HeaderPolicy
+	}
+	return HeaderPolicy_DEFAULT_HEADERS
+}
+
+func (x *ProcessRequest) GetMultiHitRatio() float32 {
+	if x != nil {
+		return x.MultiHitRatio
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetMultiHitAction() MultiHitAction {
+	if x != nil {
+		return x.MultiHitAction
+	}
+	return MultiHitAction_DEFAULT_MULTI_HIT
+}
+
+func (x *ProcessRequest) GetQcMinDepth() int32 {
+	if x != nil {
+		return x.QcMinDepth
+	}
+	return 0
+}
+
+// ProcessResponse
+type ProcessResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// api version
+	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	// identifier for the sample that processing was started for (used to monitor or cancel the sample)
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ProcessResponse) Reset() {
+	*x = ProcessResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessResponse) ProtoMessage() {}
+
+func (x *ProcessResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessResponse.ProtoReflect.Descriptor instead.
+func (*ProcessResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ProcessResponse) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *ProcessResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// CancelRequest will cancel processing for a sample.
+type CancelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// api version
+	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	// identifier for the sample
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CancelRequest) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *CancelRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// CancelResponse.
+type CancelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CancelResponse) Reset() {
+	*x = CancelResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelResponse) ProtoMessage() {}
+
+func (x *CancelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelResponse.ProtoReflect.Descriptor instead.
+func (*CancelResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{11}
+}
+
+// GetInfoRequest will query the sample records held by Archer.
+// Unset fields are not used to filter the records.
+type GetInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// api version
+	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	// sampleIDs restricts the query to these samples
+	SampleIDs []string `protobuf:"bytes,2,rep,name=sampleIDs,proto3" json:"sampleIDs,omitempty"`
+	// states restricts the query to samples in any of these states
+	States []State `protobuf:"varint,3,rep,packed,name=states,proto3,enum=v1.State" json:"states,omitempty"`
+	// startedAfter restricts the query to samples that started processing at or after this time
+	StartedAfter *timestamp.Timestamp `protobuf:"bytes,4,opt,name=startedAfter,proto3" json:"startedAfter,omitempty"`
+	// startedBefore restricts the query to samples that started processing before this time
+	StartedBefore *timestamp.Timestamp `protobuf:"bytes,5,opt,name=startedBefore,proto3" json:"startedBefore,omitempty"`
+	// endedAfter restricts the query to samples that finished processing at or after this time
+	EndedAfter *timestamp.Timestamp `protobuf:"bytes,6,opt,name=endedAfter,proto3" json:"endedAfter,omitempty"`
+	// endedBefore restricts the query to samples that finished processing before this time
+	EndedBefore *timestamp.Timestamp `protobuf:"bytes,7,opt,name=endedBefore,proto3" json:"endedBefore,omitempty"`
+	// scheme restricts the query to samples processed against this amplicon scheme (the scheme name or any of its aliases in the manifest)
+	Scheme string `protobuf:"bytes,8,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	// pageSize is the maximum number of samples in each streamed response (default 100)
+	PageSize int32 `protobuf:"varint,9,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	// limit is the maximum number of samples to return in total (0 returns all matches)
+	Limit int32 `protobuf:"varint,10,opt,name=limit,proto3" json:"limit,omitempty"`
+	// cursor resumes the query after the sample with this ID (as returned in nextCursor)
+	Cursor string `protobuf:"bytes,11,opt,name=cursor,proto3" json:"cursor,omitempty"`
+}
+
+func (x *GetInfoRequest) Reset() {
+	*x = GetInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_v1_archer_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoRequest) ProtoMessage() {}
+
+func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetInfoRequest) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *GetInfoRequest) GetSampleIDs() []string {
+	if x != nil {
+		return x.SampleIDs
+	}
+	return nil
+}
+
+func (x *GetInfoRequest) GetStates() []State {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *GetInfoRequest) GetStartedAfter() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartedAfter
+	}
+	return nil
+}
+
+func (x *GetInfoRequest) GetStartedBefore() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartedBefore
+	}
+	return nil
+}
+
+func (x *GetInfoRequest) GetEndedAfter() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndedAfter
+	}
+	return nil
+}
+
+func (x *GetInfoRequest) GetEndedBefore() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndedBefore
+	}
+	return nil
 }
 
-func (*ProcessResponse) ProtoMessage() {}
-
-func (x *ProcessResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_archer_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *GetInfoRequest) GetScheme() string {
+	if x != nil {
+		return x.Scheme
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use ProcessResponse.ProtoReflect.Descriptor instead.
-func (*ProcessResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{3}
+func (x *GetInfoRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
 }
 
-func (x *ProcessResponse) GetApiVersion() string {
+func (x *GetInfoRequest) GetLimit() int32 {
 	if x != nil {
-		return x.ApiVersion
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-func (x *ProcessResponse) GetId() string {
+func (x *GetInfoRequest) GetCursor() string {
 	if x != nil {
-		return x.Id
+		return x.Cursor
 	}
 	return ""
 }
 
-// CancelRequest will cancel processing for a sample.
-type CancelRequest struct {
+// GetInfoResponse is a page of samples matching a GetInfoRequest.
+type GetInfoResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// api version
 	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
-	// identifier for the sample
-	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// samples in this page
+	Samples []*SampleInfo `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples,omitempty"`
+	// nextCursor can be used to resume the query after this page (empty once all matches have been sent)
+	NextCursor string `protobuf:"bytes,3,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
 }
 
-func (x *CancelRequest) Reset() {
-	*x = CancelRequest{}
+func (x *GetInfoResponse) Reset() {
+	*x = GetInfoResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_v1_archer_proto_msgTypes[4]
+		mi := &file_api_proto_v1_archer_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CancelRequest) String() string {
+func (x *GetInfoResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelRequest) ProtoMessage() {}
+func (*GetInfoResponse) ProtoMessage() {}
 
-func (x *CancelRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_archer_proto_msgTypes[4]
+func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_archer_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -490,62 +2096,30 @@ func (x *CancelRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
-func (*CancelRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetInfoResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *CancelRequest) GetApiVersion() string {
+func (x *GetInfoResponse) GetApiVersion() string {
 	if x != nil {
 		return x.ApiVersion
 	}
 	return ""
 }
 
-func (x *CancelRequest) GetId() string {
+func (x *GetInfoResponse) GetSamples() []*SampleInfo {
 	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-// CancelResponse.
-type CancelResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-}
-
-func (x *CancelResponse) Reset() {
-	*x = CancelResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_v1_archer_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		return x.Samples
 	}
+	return nil
 }
 
-func (x *CancelResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*CancelResponse) ProtoMessage() {}
-
-func (x *CancelResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_archer_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *GetInfoResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use CancelResponse.ProtoReflect.Descriptor instead.
-func (*CancelResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{5}
+	return ""
 }
 
 // WatchRequest to monitor sample processing.
@@ -558,12 +2132,14 @@ type WatchRequest struct {
 	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
 	// sendFinished will tell Archer to also send information on samples that have completed procesing
 	SendFinished bool `protobuf:"varint,2,opt,name=sendFinished,proto3" json:"sendFinished,omitempty"`
+	// sendProgress will tell Archer to also send periodic progress updates for running samples
+	SendProgress bool `protobuf:"varint,3,opt,name=sendProgress,proto3" json:"sendProgress,omitempty"`
 }
 
 func (x *WatchRequest) Reset() {
 	*x = WatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_v1_archer_proto_msgTypes[6]
+		mi := &file_api_proto_v1_archer_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -576,7 +2152,7 @@ func (x *WatchRequest) String() string {
 func (*WatchRequest) ProtoMessage() {}
 
 func (x *WatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_archer_proto_msgTypes[6]
+	mi := &file_api_proto_v1_archer_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -589,7 +2165,7 @@ func (x *WatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
 func (*WatchRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{6}
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *WatchRequest) GetApiVersion() string {
@@ -606,6 +2182,13 @@ func (x *WatchRequest) GetSendFinished() bool {
 	return false
 }
 
+func (x *WatchRequest) GetSendProgress() bool {
+	if x != nil {
+		return x.SendProgress
+	}
+	return false
+}
+
 // WatchResponse.
 type WatchResponse struct {
 	state         protoimpl.MessageState
@@ -616,12 +2199,18 @@ type WatchResponse struct {
 	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
 	// current state of samples
 	Samples []*SampleInfo `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples,omitempty"`
+	// eventType is the reason this response was sent
+	EventType EventType `protobuf:"varint,3,opt,name=eventType,proto3,enum=v1.EventType" json:"eventType,omitempty"`
+	// previousState of the sample (for STATE_CHANGE events)
+	PreviousState State `protobuf:"varint,4,opt,name=previousState,proto3,enum=v1.State" json:"previousState,omitempty"`
+	// progress of the sample (for PROGRESS events)
+	Progress *SampleProgress `protobuf:"bytes,5,opt,name=progress,proto3" json:"progress,omitempty"`
 }
 
 func (x *WatchResponse) Reset() {
 	*x = WatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_v1_archer_proto_msgTypes[7]
+		mi := &file_api_proto_v1_archer_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -634,7 +2223,7 @@ func (x *WatchResponse) String() string {
 func (*WatchResponse) ProtoMessage() {}
 
 func (x *WatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_archer_proto_msgTypes[7]
+	mi := &file_api_proto_v1_archer_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -647,7 +2236,7 @@ func (x *WatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
 func (*WatchResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_v1_archer_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *WatchResponse) GetApiVersion() string {
@@ -664,6 +2253,27 @@ func (x *WatchResponse) GetSamples() []*SampleInfo {
 	return nil
 }
 
+func (x *WatchResponse) GetEventType() EventType {
+	if x != nil {
+		return x.EventType
+	}
+	return EventType_SNAPSHOT
+}
+
+func (x *WatchResponse) GetPreviousState() State {
+	if x != nil {
+		return x.PreviousState
+	}
+	return State_UNKNOWN
+}
+
+func (x *WatchResponse) GetProgress() *SampleProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
 var File_api_proto_v1_archer_proto protoreflect.FileDescriptor
 
 var file_api_proto_v1_archer_proto_rawDesc = []byte{
@@ -671,7 +2281,7 @@ var file_api_proto_v1_archer_proto_rawDesc = []byte{
 	0x72, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x76, 0x31, 0x1a,
 	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
 	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x22, 0xcb, 0x02, 0x0a, 0x0b, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x22, 0xac, 0x0d, 0x0a, 0x0b, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73,
 	0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x61, 0x64, 0x73, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x61, 0x64, 0x73,
 	0x12, 0x1c, 0x0a, 0x09, 0x6b, 0x65, 0x70, 0x74, 0x52, 0x65, 0x61, 0x64, 0x73, 0x18, 0x02, 0x20,
@@ -687,84 +2297,386 @@ var file_api_proto_v1_archer_proto_rawDesc = []byte{
 	0x09, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x4d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
 	0x52, 0x09, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x4d, 0x61, 0x78, 0x12, 0x1c, 0x0a, 0x09, 0x6c,
 	0x65, 0x6e, 0x67, 0x74, 0x68, 0x4d, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
-	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x4d, 0x69, 0x6e, 0x1a, 0x43, 0x0a, 0x15, 0x41, 0x6d, 0x70,
-	0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x88,
-	0x03, 0x0a, 0x0a, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x0a,
-	0x08, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44, 0x12, 0x3a, 0x0a, 0x0e, 0x70, 0x72, 0x6f,
-	0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x09, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
-	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73,
-	0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x28,
-	0x0a, 0x0f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65,
-	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x44, 0x69,
-	0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72,
-	0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69,
-	0x6d, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
-	0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x63,
-	0x65, 0x73, 0x73, 0x53, 0x74, 0x61, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
-	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
-	0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1a, 0x0a,
-	0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x22, 0xb4, 0x01, 0x0a, 0x0e, 0x50, 0x72,
-	0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a,
-	0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08,
-	0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44, 0x12, 0x28, 0x0a, 0x0f, 0x69, 0x6e, 0x70, 0x75,
-	0x74, 0x46, 0x41, 0x53, 0x54, 0x51, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x0f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x46, 0x41, 0x53, 0x54, 0x51, 0x66, 0x69, 0x6c,
-	0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x63,
-	0x68, 0x65, 0x6d, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x22, 0x41, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73,
-	0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x69, 0x64, 0x22, 0x3f, 0x0a, 0x0d, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71,
+	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x4d, 0x69, 0x6e, 0x12, 0x25, 0x0a, 0x07, 0x73, 0x63, 0x6f,
+	0x72, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x63, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x73, 0x63, 0x6f, 0x72, 0x69, 0x6e, 0x67,
+	0x12, 0x26, 0x0a, 0x0e, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f,
+	0x6c, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0e, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x54,
+	0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x6d, 0x65, 0x72,
+	0x53, 0x69, 0x7a, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6b, 0x6d, 0x65, 0x72,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x53, 0x69,
+	0x7a, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x6b, 0x65, 0x74, 0x63, 0x68,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61,
+	0x6c, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63,
+	0x61, 0x6c, 0x12, 0x26, 0x0a, 0x0e, 0x6d, 0x69, 0x6e, 0x4d, 0x65, 0x61, 0x6e, 0x51, 0x75, 0x61,
+	0x6c, 0x69, 0x74, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0e, 0x6d, 0x69, 0x6e, 0x4d,
+	0x65, 0x61, 0x6e, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x72,
+	0x69, 0x6d, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
+	0x74, 0x72, 0x69, 0x6d, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x72,
+	0x69, 0x6d, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x0b, 0x74, 0x72, 0x69, 0x6d, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x22, 0x0a, 0x0c,
+	0x74, 0x72, 0x69, 0x6d, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x73, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0c, 0x74, 0x72, 0x69, 0x6d, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x73,
+	0x12, 0x34, 0x0a, 0x0c, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x73,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x72, 0x6f, 0x70,
+	0x70, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x73, 0x52, 0x0c, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65,
+	0x64, 0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74,
+	0x79, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x18, 0x11, 0x20, 0x03, 0x28, 0x05,
+	0x52, 0x10, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72,
+	0x61, 0x6d, 0x12, 0x32, 0x0a, 0x14, 0x6b, 0x65, 0x70, 0x74, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74,
+	0x79, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x18, 0x12, 0x20, 0x03, 0x28, 0x05,
+	0x52, 0x14, 0x6b, 0x65, 0x70, 0x74, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x12, 0x2e, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x72,
+	0x54, 0x72, 0x69, 0x6d, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x54, 0x72, 0x69, 0x6d, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x6d,
+	0x65, 0x72, 0x54, 0x72, 0x69, 0x6d, 0x12, 0x3d, 0x0a, 0x0f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x72,
+	0x54, 0x72, 0x69, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x54, 0x72, 0x69, 0x6d, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x0f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x54, 0x72, 0x69, 0x6d,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x41, 0x6d, 0x70, 0x6c,
+	0x69, 0x63, 0x6f, 0x6e, 0x44, 0x65, 0x70, 0x74, 0x68, 0x18, 0x15, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x10, 0x6d, 0x61, 0x78, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x44, 0x65, 0x70, 0x74,
+	0x68, 0x12, 0x57, 0x0a, 0x12, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x73, 0x65, 0x64, 0x43,
+	0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x16, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x4e,
+	0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x73, 0x65, 0x64, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x12, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x73,
+	0x65, 0x64, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x68, 0x6f,
+	0x73, 0x74, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x17, 0x20, 0x01, 0x28,
+	0x02, 0x52, 0x0d, 0x68, 0x6f, 0x73, 0x74, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64,
+	0x12, 0x34, 0x0a, 0x0c, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0c, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x4d, 0x61, 0x70, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x4d, 0x61, 0x70, 0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x48, 0x69, 0x74,
+	0x52, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0d, 0x6d, 0x75, 0x6c,
+	0x74, 0x69, 0x48, 0x69, 0x74, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x3a, 0x0a, 0x0e, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x48, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x1b, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x48, 0x69, 0x74,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x48, 0x69, 0x74,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x61, 0x6d, 0x62, 0x69, 0x67, 0x75,
+	0x6f, 0x75, 0x73, 0x52, 0x65, 0x61, 0x64, 0x73, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
+	0x61, 0x6d, 0x62, 0x69, 0x67, 0x75, 0x6f, 0x75, 0x73, 0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x24,
+	0x0a, 0x0d, 0x63, 0x68, 0x69, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x52, 0x65, 0x61, 0x64, 0x73, 0x18,
+	0x1d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x63, 0x68, 0x69, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x52,
+	0x65, 0x61, 0x64, 0x73, 0x12, 0x3c, 0x0a, 0x09, 0x70, 0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x18, 0x1e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x61, 0x6d,
+	0x70, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x70, 0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x12, 0x4b, 0x0a, 0x0e, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x18, 0x1f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x52, 0x65, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0e, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x1a,
+	0x43, 0x0a, 0x15, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x43, 0x6f, 0x76, 0x65, 0x72,
+	0x61, 0x67, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x1a, 0x45, 0x0a, 0x17, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x73,
+	0x65, 0x64, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x4b, 0x0a, 0x0e, 0x50,
+	0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x23, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x55, 0x0a, 0x13, 0x52, 0x65, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x28, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0xe0, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73,
+	0x12, 0x2a, 0x0a, 0x10, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64, 0x41, 0x6d, 0x70, 0x6c, 0x69,
+	0x63, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x65, 0x64, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x72, 0x65, 0x61, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x72, 0x65, 0x61,
+	0x64, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x73, 0x65, 0x64,
+	0x52, 0x65, 0x61, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x6e, 0x6f, 0x72,
+	0x6d, 0x61, 0x6c, 0x69, 0x73, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6c, 0x65,
+	0x6e, 0x67, 0x74, 0x68, 0x12, 0x2c, 0x0a, 0x11, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x11, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61,
+	0x67, 0x65, 0x22, 0xa7, 0x03, 0x0a, 0x08, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x51, 0x43, 0x12,
+	0x27, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x43, 0x56, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x44,
+	0x65, 0x70, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x44,
+	0x65, 0x70, 0x74, 0x68, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x41, 0x6d,
+	0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x70,
+	0x61, 0x73, 0x73, 0x65, 0x64, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73, 0x12, 0x26,
+	0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x41, 0x6d, 0x70,
+	0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65,
+	0x64, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x10, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f,
+	0x6e, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x43,
+	0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02, 0x52, 0x11, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65,
+	0x12, 0x22, 0x0a, 0x0c, 0x77, 0x61, 0x72, 0x6e, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0c, 0x77, 0x61, 0x72, 0x6e, 0x43, 0x6f, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x43, 0x6f, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0c, 0x66, 0x61, 0x69, 0x6c,
+	0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x66, 0x66, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x6f, 0x66, 0x66,
+	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x67, 0x61, 0x74, 0x65, 0x46, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x67, 0x61,
+	0x74, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x71, 0x75,
+	0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0b, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x22, 0xe5, 0x01, 0x0a,
+	0x09, 0x50, 0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6d,
+	0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x61,
+	0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x65, 0x64, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x10, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64, 0x41, 0x6d, 0x70, 0x6c, 0x69,
+	0x63, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x61, 0x64, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x72, 0x65, 0x61, 0x64, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x6e, 0x6f,
+	0x72, 0x6d, 0x61, 0x6c, 0x69, 0x73, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0f, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x73, 0x65, 0x64, 0x52,
+	0x65, 0x61, 0x64, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x65, 0x61, 0x6e, 0x43, 0x6f, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0c, 0x6d, 0x65, 0x61, 0x6e,
+	0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x72, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x76, 0x65, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x02, 0x52, 0x10, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x43, 0x6f, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x22, 0x9d, 0x01, 0x0a, 0x0f, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x54,
+	0x72, 0x69, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x72, 0x69, 0x6d,
+	0x6d, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c,
+	0x74, 0x72, 0x69, 0x6d, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x73,
+	0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x73,
+	0x12, 0x22, 0x0a, 0x0c, 0x74, 0x72, 0x69, 0x6d, 0x6d, 0x65, 0x64, 0x42, 0x61, 0x73, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x74, 0x72, 0x69, 0x6d, 0x6d, 0x65, 0x64, 0x42,
+	0x61, 0x73, 0x65, 0x73, 0x22, 0xa2, 0x01, 0x0a, 0x0c, 0x44, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64,
+	0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x18, 0x0a,
+	0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
+	0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x48, 0x69, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6e, 0x6f, 0x48, 0x69, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x64, 0x65, 0x70, 0x74, 0x68, 0x43, 0x61, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x64, 0x65, 0x70, 0x74, 0x68, 0x43, 0x61, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x48, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x48, 0x69, 0x74, 0x22, 0x96, 0x01, 0x0a, 0x0e, 0x53, 0x61,
+	0x6d, 0x70, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x61, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x6b, 0x65, 0x70, 0x74, 0x52, 0x65, 0x61, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x6b, 0x65, 0x70, 0x74, 0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x24, 0x0a, 0x0d,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x65, 0x64, 0x22, 0xcc, 0x03, 0x0a, 0x0a, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44, 0x12, 0x3a, 0x0a,
+	0x0e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x09, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x44, 0x69, 0x73, 0x63, 0x6f,
+	0x76, 0x65, 0x72, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x66, 0x69, 0x6c,
+	0x65, 0x73, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64, 0x12, 0x38, 0x0a, 0x09,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x0c,
+	0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x53, 0x74, 0x61, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x73, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
+	0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x08, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x51, 0x43, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x51, 0x43, 0x52, 0x08, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x51,
+	0x43, 0x22, 0xd3, 0x06, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71,
 	0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69,
 	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x22, 0x10, 0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x52, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x46, 0x69,
-	0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x65,
-	0x6e, 0x64, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x22, 0x59, 0x0a, 0x0d, 0x57, 0x61,
-	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x61,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44,
+	0x12, 0x28, 0x0a, 0x0f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x46, 0x41, 0x53, 0x54, 0x51, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x69, 0x6e, 0x70, 0x75, 0x74,
+	0x46, 0x41, 0x53, 0x54, 0x51, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63,
+	0x68, 0x65, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65,
+	0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d,
+	0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x07, 0x73, 0x63, 0x6f, 0x72,
+	0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x63, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x73, 0x63, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x12,
+	0x26, 0x0a, 0x0e, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0e, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x54, 0x68,
+	0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x6d, 0x65, 0x72, 0x53,
+	0x69, 0x7a, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6b, 0x6d, 0x65, 0x72, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x53, 0x69, 0x7a,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x2e, 0x0a, 0x0a, 0x6b, 0x6d, 0x65, 0x72, 0x53, 0x74, 0x72, 0x61, 0x6e,
+	0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x6d, 0x65,
+	0x72, 0x53, 0x74, 0x72, 0x61, 0x6e, 0x64, 0x52, 0x0a, 0x6b, 0x6d, 0x65, 0x72, 0x53, 0x74, 0x72,
+	0x61, 0x6e, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x69, 0x6e, 0x4c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12,
+	0x28, 0x0a, 0x0f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x54, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e,
+	0x63, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x54, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x6d, 0x69, 0x6e,
+	0x4d, 0x65, 0x61, 0x6e, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x02, 0x52, 0x0e, 0x6d, 0x69, 0x6e, 0x4d, 0x65, 0x61, 0x6e, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74,
+	0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x72, 0x69, 0x6d, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x72, 0x69, 0x6d, 0x57, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x72, 0x69, 0x6d, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0b, 0x74, 0x72, 0x69, 0x6d, 0x51, 0x75, 0x61, 0x6c,
+	0x69, 0x74, 0x79, 0x12, 0x2e, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x54, 0x72, 0x69,
+	0x6d, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69,
+	0x6d, 0x65, 0x72, 0x54, 0x72, 0x69, 0x6d, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x54,
+	0x72, 0x69, 0x6d, 0x12, 0x2a, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63,
+	0x6f, 0x6e, 0x44, 0x65, 0x70, 0x74, 0x68, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x6d,
+	0x61, 0x78, 0x41, 0x6d, 0x70, 0x6c, 0x69, 0x63, 0x6f, 0x6e, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12,
+	0x34, 0x0a, 0x0c, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18,
+	0x13, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0c, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x48, 0x69,
+	0x74, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x14, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0d, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x48, 0x69, 0x74, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x3a, 0x0a, 0x0e, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x48, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x15, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x48, 0x69,
+	0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x48, 0x69,
+	0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x71, 0x63, 0x4d, 0x69, 0x6e,
+	0x44, 0x65, 0x70, 0x74, 0x68, 0x18, 0x16, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x71, 0x63, 0x4d,
+	0x69, 0x6e, 0x44, 0x65, 0x70, 0x74, 0x68, 0x22, 0x41, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70,
+	0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3f, 0x0a, 0x0d, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x61,
 	0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x07, 0x73,
-	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x76,
-	0x31, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x73, 0x61,
-	0x6d, 0x70, 0x6c, 0x65, 0x73, 0x2a, 0x48, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b,
-	0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x52,
-	0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x55, 0x43, 0x43,
-	0x45, 0x53, 0x53, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x03,
-	0x12, 0x0d, 0x0a, 0x09, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x32,
-	0xa3, 0x01, 0x0a, 0x06, 0x41, 0x72, 0x63, 0x68, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x07, 0x50, 0x72,
-	0x6f, 0x63, 0x65, 0x73, 0x73, 0x12, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65,
-	0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x50,
-	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x31, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x11, 0x2e, 0x76, 0x31, 0x2e,
-	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e,
-	0x76, 0x31, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x10, 0x2e, 0x76,
-	0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11,
-	0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x30, 0x01, 0x42, 0x08, 0x5a, 0x06, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x10, 0x0a, 0x0e, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xcf, 0x03,
+	0x0a, 0x0e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x1c, 0x0a, 0x09, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x09, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x12, 0x21,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x09,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x73, 0x12, 0x3e, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x66, 0x74, 0x65,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x66, 0x74, 0x65,
+	0x72, 0x12, 0x40, 0x0a, 0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x42, 0x65, 0x66, 0x6f,
+	0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x42, 0x65, 0x66,
+	0x6f, 0x72, 0x65, 0x12, 0x3a, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x41, 0x66, 0x74, 0x65,
+	0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12,
+	0x3c, 0x0a, 0x0b, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0b, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x63, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f,
+	0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x22,
+	0x7b, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0x76, 0x0a, 0x0c,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a,
+	0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c,
+	0x73, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64,
+	0x12, 0x22, 0x0a, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x22, 0xe7, 0x01, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x61, 0x6d,
+	0x70, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73,
+	0x12, 0x2b, 0x0a, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2f, 0x0a,
+	0x0d, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x09, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x0d, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2e,
+	0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x2a, 0x39,
+	0x0a, 0x09, 0x51, 0x43, 0x56, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x12, 0x0e, 0x0a, 0x0a, 0x4e,
+	0x4f, 0x5f, 0x56, 0x45, 0x52, 0x44, 0x49, 0x43, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x50,
+	0x41, 0x53, 0x53, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x57, 0x41, 0x52, 0x4e, 0x10, 0x02, 0x12,
+	0x08, 0x0a, 0x04, 0x46, 0x41, 0x49, 0x4c, 0x10, 0x03, 0x2a, 0x57, 0x0a, 0x05, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12,
+	0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07,
+	0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45,
+	0x44, 0x10, 0x04, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x51, 0x43,
+	0x10, 0x05, 0x2a, 0x45, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x0c, 0x0a, 0x08, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f, 0x54, 0x10, 0x00, 0x12, 0x0a, 0x0a,
+	0x06, 0x51, 0x55, 0x45, 0x55, 0x45, 0x44, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x54, 0x41,
+	0x54, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x50,
+	0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53, 0x10, 0x03, 0x2a, 0x27, 0x0a, 0x07, 0x53, 0x63, 0x6f,
+	0x72, 0x69, 0x6e, 0x67, 0x12, 0x0b, 0x0a, 0x07, 0x4a, 0x41, 0x43, 0x43, 0x41, 0x52, 0x44, 0x10,
+	0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x4f, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x4d, 0x45, 0x4e, 0x54,
+	0x10, 0x01, 0x2a, 0x3c, 0x0a, 0x0a, 0x4b, 0x6d, 0x65, 0x72, 0x53, 0x74, 0x72, 0x61, 0x6e, 0x64,
+	0x12, 0x12, 0x0a, 0x0e, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x53, 0x54, 0x52, 0x41,
+	0x4e, 0x44, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x41, 0x4e, 0x4f, 0x4e, 0x49, 0x43, 0x41,
+	0x4c, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x10, 0x02,
+	0x2a, 0x49, 0x0a, 0x0a, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x72, 0x54, 0x72, 0x69, 0x6d, 0x12, 0x10,
+	0x0a, 0x0c, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x54, 0x52, 0x49, 0x4d, 0x10, 0x00,
+	0x12, 0x0b, 0x0a, 0x07, 0x4e, 0x4f, 0x5f, 0x54, 0x52, 0x49, 0x4d, 0x10, 0x01, 0x12, 0x0d, 0x0a,
+	0x09, 0x53, 0x4f, 0x46, 0x54, 0x5f, 0x54, 0x52, 0x49, 0x4d, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09,
+	0x48, 0x41, 0x52, 0x44, 0x5f, 0x54, 0x52, 0x49, 0x4d, 0x10, 0x03, 0x2a, 0x68, 0x0a, 0x0e, 0x4d,
+	0x75, 0x6c, 0x74, 0x69, 0x48, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x15, 0x0a,
+	0x11, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x5f, 0x48,
+	0x49, 0x54, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x4b, 0x45, 0x45, 0x50, 0x5f, 0x4d, 0x55, 0x4c,
+	0x54, 0x49, 0x5f, 0x48, 0x49, 0x54, 0x10, 0x01, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x49, 0x53, 0x43,
+	0x41, 0x52, 0x44, 0x5f, 0x43, 0x48, 0x49, 0x4d, 0x45, 0x52, 0x49, 0x43, 0x10, 0x02, 0x12, 0x15,
+	0x0a, 0x11, 0x44, 0x49, 0x53, 0x43, 0x41, 0x52, 0x44, 0x5f, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x5f,
+	0x48, 0x49, 0x54, 0x10, 0x03, 0x2a, 0x5c, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x13, 0x0a, 0x0f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54,
+	0x5f, 0x48, 0x45, 0x41, 0x44, 0x45, 0x52, 0x53, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4b, 0x45,
+	0x45, 0x50, 0x5f, 0x48, 0x45, 0x41, 0x44, 0x45, 0x52, 0x53, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d,
+	0x49, 0x4e, 0x44, 0x45, 0x58, 0x5f, 0x48, 0x45, 0x41, 0x44, 0x45, 0x52, 0x53, 0x10, 0x02, 0x12,
+	0x12, 0x0a, 0x0e, 0x53, 0x41, 0x4d, 0x50, 0x4c, 0x45, 0x5f, 0x48, 0x45, 0x41, 0x44, 0x45, 0x52,
+	0x53, 0x10, 0x03, 0x32, 0xdb, 0x01, 0x0a, 0x06, 0x41, 0x72, 0x63, 0x68, 0x65, 0x72, 0x12, 0x34,
+	0x0a, 0x07, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x12, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x11,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12,
+	0x30, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30,
+	0x01, 0x42, 0x08, 0x5a, 0x06, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -779,40 +2691,87 @@ func file_api_proto_v1_archer_proto_rawDescGZIP() []byte {
 	return file_api_proto_v1_archer_proto_rawDescData
 }
 
-var file_api_proto_v1_archer_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_proto_v1_archer_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_api_proto_v1_archer_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_api_proto_v1_archer_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_api_proto_v1_archer_proto_goTypes = []interface{}{
-	(State)(0),                  // 0: v1.State
-	(*SampleStats)(nil),         // 1: v1.SampleStats
-	(*SampleInfo)(nil),          // 2: v1.SampleInfo
-	(*ProcessRequest)(nil),      // 3: v1.ProcessRequest
-	(*ProcessResponse)(nil),     // 4: v1.ProcessResponse
-	(*CancelRequest)(nil),       // 5: v1.CancelRequest
-	(*CancelResponse)(nil),      // 6: v1.CancelResponse
-	(*WatchRequest)(nil),        // 7: v1.WatchRequest
-	(*WatchResponse)(nil),       // 8: v1.WatchResponse
-	nil,                         // 9: v1.SampleStats.AmpliconCoverageEntry
-	(*timestamp.Timestamp)(nil), // 10: google.protobuf.Timestamp
+	(QCVerdict)(0),              // 0: v1.QCVerdict
+	(State)(0),                  // 1: v1.State
+	(EventType)(0),              // 2: v1.EventType
+	(Scoring)(0),                // 3: v1.Scoring
+	(KmerStrand)(0),             // 4: v1.KmerStrand
+	(PrimerTrim)(0),             // 5: v1.PrimerTrim
+	(MultiHitAction)(0),         // 6: v1.MultiHitAction
+	(HeaderPolicy)(0),           // 7: v1.HeaderPolicy
+	(*SampleStats)(nil),         // 8: v1.SampleStats
+	(*ReferenceStats)(nil),      // 9: v1.ReferenceStats
+	(*SampleQC)(nil),            // 10: v1.SampleQC
+	(*PoolStats)(nil),           // 11: v1.PoolStats
+	(*PrimerTrimStats)(nil),     // 12: v1.PrimerTrimStats
+	(*DroppedReads)(nil),        // 13: v1.DroppedReads
+	(*SampleProgress)(nil),      // 14: v1.SampleProgress
+	(*SampleInfo)(nil),          // 15: v1.SampleInfo
+	(*ProcessRequest)(nil),      // 16: v1.ProcessRequest
+	(*ProcessResponse)(nil),     // 17: v1.ProcessResponse
+	(*CancelRequest)(nil),       // 18: v1.CancelRequest
+	(*CancelResponse)(nil),      // 19: v1.CancelResponse
+	(*GetInfoRequest)(nil),      // 20: v1.GetInfoRequest
+	(*GetInfoResponse)(nil),     // 21: v1.GetInfoResponse
+	(*WatchRequest)(nil),        // 22: v1.WatchRequest
+	(*WatchResponse)(nil),       // 23: v1.WatchResponse
+	nil,                         // 24: v1.SampleStats.AmpliconCoverageEntry
+	nil,                         // 25: v1.SampleStats.NormalisedCoverageEntry
+	nil,                         // 26: v1.SampleStats.PoolStatsEntry
+	nil,                         // 27: v1.SampleStats.ReferenceStatsEntry
+	(*timestamp.Timestamp)(nil), // 28: google.protobuf.Timestamp
 }
 var file_api_proto_v1_archer_proto_depIdxs = []int32{
-	9,  // 0: v1.SampleStats.ampliconCoverage:type_name -> v1.SampleStats.AmpliconCoverageEntry
-	3,  // 1: v1.SampleInfo.processRequest:type_name -> v1.ProcessRequest
-	0,  // 2: v1.SampleInfo.state:type_name -> v1.State
-	10, // 3: v1.SampleInfo.startTime:type_name -> google.protobuf.Timestamp
-	10, // 4: v1.SampleInfo.endTime:type_name -> google.protobuf.Timestamp
-	1,  // 5: v1.SampleInfo.processStats:type_name -> v1.SampleStats
-	2,  // 6: v1.WatchResponse.samples:type_name -> v1.SampleInfo
-	3,  // 7: v1.Archer.Process:input_type -> v1.ProcessRequest
-	5,  // 8: v1.Archer.Cancel:input_type -> v1.CancelRequest
-	7,  // 9: v1.Archer.Watch:input_type -> v1.WatchRequest
-	4,  // 10: v1.Archer.Process:output_type -> v1.ProcessResponse
-	6,  // 11: v1.Archer.Cancel:output_type -> v1.CancelResponse
-	8,  // 12: v1.Archer.Watch:output_type -> v1.WatchResponse
-	10, // [10:13] is the sub-list for method output_type
-	7,  // [7:10] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	24, // 0: v1.SampleStats.ampliconCoverage:type_name -> v1.SampleStats.AmpliconCoverageEntry
+	3,  // 1: v1.SampleStats.scoring:type_name -> v1.Scoring
+	13, // 2: v1.SampleStats.droppedReads:type_name -> v1.DroppedReads
+	5,  // 3: v1.SampleStats.primerTrim:type_name -> v1.PrimerTrim
+	12, // 4: v1.SampleStats.primerTrimStats:type_name -> v1.PrimerTrimStats
+	25, // 5: v1.SampleStats.normalisedCoverage:type_name -> v1.SampleStats.NormalisedCoverageEntry
+	7,  // 6: v1.SampleStats.headerPolicy:type_name -> v1.HeaderPolicy
+	6,  // 7: v1.SampleStats.multiHitAction:type_name -> v1.MultiHitAction
+	26, // 8: v1.SampleStats.poolStats:type_name -> v1.SampleStats.PoolStatsEntry
+	27, // 9: v1.SampleStats.referenceStats:type_name -> v1.SampleStats.ReferenceStatsEntry
+	0,  // 10: v1.SampleQC.verdict:type_name -> v1.QCVerdict
+	16, // 11: v1.SampleInfo.processRequest:type_name -> v1.ProcessRequest
+	1,  // 12: v1.SampleInfo.state:type_name -> v1.State
+	28, // 13: v1.SampleInfo.startTime:type_name -> google.protobuf.Timestamp
+	28, // 14: v1.SampleInfo.endTime:type_name -> google.protobuf.Timestamp
+	8,  // 15: v1.SampleInfo.processStats:type_name -> v1.SampleStats
+	10, // 16: v1.SampleInfo.sampleQC:type_name -> v1.SampleQC
+	3,  // 17: v1.ProcessRequest.scoring:type_name -> v1.Scoring
+	4,  // 18: v1.ProcessRequest.kmerStrand:type_name -> v1.KmerStrand
+	5,  // 19: v1.ProcessRequest.primerTrim:type_name -> v1.PrimerTrim
+	7,  // 20: v1.ProcessRequest.headerPolicy:type_name -> v1.HeaderPolicy
+	6,  // 21: v1.ProcessRequest.multiHitAction:type_name -> v1.MultiHitAction
+	1,  // 22: v1.GetInfoRequest.states:type_name -> v1.State
+	28, // 23: v1.GetInfoRequest.startedAfter:type_name -> google.protobuf.Timestamp
+	28, // 24: v1.GetInfoRequest.startedBefore:type_name -> google.protobuf.Timestamp
+	28, // 25: v1.GetInfoRequest.endedAfter:type_name -> google.protobuf.Timestamp
+	28, // 26: v1.GetInfoRequest.endedBefore:type_name -> google.protobuf.Timestamp
+	15, // 27: v1.GetInfoResponse.samples:type_name -> v1.SampleInfo
+	15, // 28: v1.WatchResponse.samples:type_name -> v1.SampleInfo
+	2,  // 29: v1.WatchResponse.eventType:type_name -> v1.EventType
+	1,  // 30: v1.WatchResponse.previousState:type_name -> v1.State
+	14, // 31: v1.WatchResponse.progress:type_name -> v1.SampleProgress
+	11, // 32: v1.SampleStats.PoolStatsEntry.value:type_name -> v1.PoolStats
+	9,  // 33: v1.SampleStats.ReferenceStatsEntry.value:type_name -> v1.ReferenceStats
+	16, // 34: v1.Archer.Process:input_type -> v1.ProcessRequest
+	18, // 35: v1.Archer.Cancel:input_type -> v1.CancelRequest
+	20, // 36: v1.Archer.GetInfo:input_type -> v1.GetInfoRequest
+	22, // 37: v1.Archer.Watch:input_type -> v1.WatchRequest
+	17, // 38: v1.Archer.Process:output_type -> v1.ProcessResponse
+	19, // 39: v1.Archer.Cancel:output_type -> v1.CancelResponse
+	21, // 40: v1.Archer.GetInfo:output_type -> v1.GetInfoResponse
+	23, // 41: v1.Archer.Watch:output_type -> v1.WatchResponse
+	38, // [38:42] is the sub-list for method output_type
+	34, // [34:38] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_v1_archer_proto_init() }
@@ -834,7 +2793,7 @@ func file_api_proto_v1_archer_proto_init() {
 			}
 		}
 		file_api_proto_v1_archer_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SampleInfo); i {
+			switch v := v.(*ReferenceStats); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -846,7 +2805,7 @@ func file_api_proto_v1_archer_proto_init() {
 			}
 		}
 		file_api_proto_v1_archer_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProcessRequest); i {
+			switch v := v.(*SampleQC); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -858,7 +2817,7 @@ func file_api_proto_v1_archer_proto_init() {
 			}
 		}
 		file_api_proto_v1_archer_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProcessResponse); i {
+			switch v := v.(*PoolStats); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -870,7 +2829,7 @@ func file_api_proto_v1_archer_proto_init() {
 			}
 		}
 		file_api_proto_v1_archer_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CancelRequest); i {
+			switch v := v.(*PrimerTrimStats); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -882,7 +2841,7 @@ func file_api_proto_v1_archer_proto_init() {
 			}
 		}
 		file_api_proto_v1_archer_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CancelResponse); i {
+			switch v := v.(*DroppedReads); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -894,7 +2853,7 @@ func file_api_proto_v1_archer_proto_init() {
 			}
 		}
 		file_api_proto_v1_archer_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WatchRequest); i {
+			switch v := v.(*SampleProgress); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -906,6 +2865,102 @@ func file_api_proto_v1_archer_proto_init() {
 			}
 		}
 		file_api_proto_v1_archer_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SampleInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_v1_archer_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_v1_archer_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_v1_archer_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_v1_archer_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_v1_archer_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_v1_archer_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_v1_archer_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_v1_archer_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WatchResponse); i {
 			case 0:
 				return &v.state
@@ -923,8 +2978,8 @@ func file_api_proto_v1_archer_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_proto_v1_archer_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   9,
+			NumEnums:      8,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -955,6 +3010,8 @@ type ArcherClient interface {
 	Process(ctx context.Context, in *ProcessRequest, opts ...grpc.CallOption) (*ProcessResponse, error)
 	// Cancel will cancel processing for a sample.
 	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
+	// GetInfo returns information on one or more preparation operations.
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (Archer_GetInfoClient, error)
 	// Watch sample processing, returning messages when sample processing starts, stops or updates
 	// The current state of all currently-processing samples will be returned in the initial set
 	// of messages, with the option of also including finished samples.
@@ -987,8 +3044,40 @@ func (c *archerClient) Cancel(ctx context.Context, in *CancelRequest, opts ...gr
 	return out, nil
 }
 
+func (c *archerClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (Archer_GetInfoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Archer_serviceDesc.Streams[0], "/v1.Archer/GetInfo", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &archerGetInfoClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Archer_GetInfoClient interface {
+	Recv() (*GetInfoResponse, error)
+	grpc.ClientStream
+}
+
+type archerGetInfoClient struct {
+	grpc.ClientStream
+}
+
+func (x *archerGetInfoClient) Recv() (*GetInfoResponse, error) {
+	m := new(GetInfoResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *archerClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Archer_WatchClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Archer_serviceDesc.Streams[0], "/v1.Archer/Watch", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Archer_serviceDesc.Streams[1], "/v1.Archer/Watch", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1025,6 +3114,8 @@ type ArcherServer interface {
 	Process(context.Context, *ProcessRequest) (*ProcessResponse, error)
 	// Cancel will cancel processing for a sample.
 	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
+	// GetInfo returns information on one or more preparation operations.
+	GetInfo(*GetInfoRequest, Archer_GetInfoServer) error
 	// Watch sample processing, returning messages when sample processing starts, stops or updates
 	// The current state of all currently-processing samples will be returned in the initial set
 	// of messages, with the option of also including finished samples.
@@ -1041,6 +3132,9 @@ func (*UnimplementedArcherServer) Process(context.Context, *ProcessRequest) (*Pr
 func (*UnimplementedArcherServer) Cancel(context.Context, *CancelRequest) (*CancelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
 }
+func (*UnimplementedArcherServer) GetInfo(*GetInfoRequest, Archer_GetInfoServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
 func (*UnimplementedArcherServer) Watch(*WatchRequest, Archer_WatchServer) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
@@ -1085,6 +3179,27 @@ func _Archer_Cancel_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Archer_GetInfo_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetInfoRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArcherServer).GetInfo(m, &archerGetInfoServer{stream})
+}
+
+type Archer_GetInfoServer interface {
+	Send(*GetInfoResponse) error
+	grpc.ServerStream
+}
+
+type archerGetInfoServer struct {
+	grpc.ServerStream
+}
+
+func (x *archerGetInfoServer) Send(m *GetInfoResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Archer_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(WatchRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1120,6 +3235,11 @@ var _Archer_serviceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetInfo",
+			Handler:       _Archer_GetInfo_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Watch",
 			Handler:       _Archer_Watch_Handler,
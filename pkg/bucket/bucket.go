@@ -1,49 +1,55 @@
-// Package bucket manages the AWS S3 bucket uploads.
+// Package bucket manages the storage of processed reads, either in an
+// AWS S3 bucket or a local directory.
 package bucket
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
-	"os"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
 const (
 
-	// AccessKeyID is the env variable to check for the AWS access key id
-	AccessKeyID = "AWS_ACCESS_KEY_ID"
-
-	// AccessSecretKey is the env variable to check for the AWS secret key
-	AccessSecretKey = "AWS_SECRET_ACCESS_KEY"
-
 	// DefaultRegion is the AWS region to use for S3 bucket upload
 	DefaultRegion = "eu-west-2"
 )
 
 var (
-	errBucketName      = errors.New("bucket name is required")
-	errBucketRegion    = errors.New("bucket region required")
-	errAccessKeyID     = errors.New("no AWS_ACCESS_KEY_ID environment variable found")
-	errAccessSecretKey = errors.New("no AWS_SECRET_ACCESS_KEY environment variable found")
+	errBucketName   = errors.New("bucket name is required")
+	errBucketRegion = errors.New("bucket region required")
 )
 
 // Bucket is used to pass AWS and S3
 // information around Archer.
 //
-// TODO: this is used to interface with the
-// S3 upload manager - I've used functional
-// option setter so that we can add more
-// fields here as needed when we have
-// a clearer brief.
+// By default it uses AWS S3, but the endpoint
+// can be set to use any S3-compatible store
+// (e.g. MinIO or Ceph). Credentials are found
+// using the AWS credential chain (environment
+// variables, shared credentials file and
+// profile, then any instance role).
 type Bucket struct {
-	name            string
-	region          string
-	accessKeyID     string
-	accessSecretKey string
+	name       string
+	prefix     string
+	region     string
+	endpoint   string
+	pathStyle  bool
+	skipVerify bool
+	profile    string
 }
 
 // Option is a wrapper struct used to pass functional
@@ -62,6 +68,15 @@ func SetName(name string) Option {
 	}
 }
 
+// SetPrefix is an option setter for the New bucket constructor
+// that sets the key prefix used for uploads to the bucket.
+func SetPrefix(prefix string) Option {
+	return func(x *Bucket) error {
+		x.prefix = prefix
+		return nil
+	}
+}
+
 // SetRegion is an option setter for the New bucket constructor
 // that sets the region field of a Bucket struct.
 func SetRegion(region string) Option {
@@ -74,6 +89,53 @@ func SetRegion(region string) Option {
 	}
 }
 
+// SetEndpoint is an option setter for the New bucket constructor
+// that sets a custom S3-compatible endpoint URL to use instead
+// of AWS (e.g. https://minio.local:9000).
+func SetEndpoint(endpoint string) Option {
+	return func(x *Bucket) error {
+		if len(endpoint) == 0 {
+			return nil
+		}
+		if err := checkEndpoint(endpoint); err != nil {
+			return err
+		}
+		x.endpoint = endpoint
+		return nil
+	}
+}
+
+// SetPathStyle is an option setter for the New bucket constructor
+// that sets path-style addressing (endpoint/bucket/key) instead of
+// virtual hosted-style (bucket.endpoint/key). Most S3-compatible
+// stores need this.
+func SetPathStyle(pathStyle bool) Option {
+	return func(x *Bucket) error {
+		x.pathStyle = pathStyle
+		return nil
+	}
+}
+
+// SetSkipVerify is an option setter for the New bucket constructor
+// that turns off TLS certificate verification for the endpoint.
+// This should only be used for testing or self-signed endpoints.
+func SetSkipVerify(skipVerify bool) Option {
+	return func(x *Bucket) error {
+		x.skipVerify = skipVerify
+		return nil
+	}
+}
+
+// SetProfile is an option setter for the New bucket constructor
+// that sets the AWS shared config profile to get credentials and
+// settings from.
+func SetProfile(profile string) Option {
+	return func(x *Bucket) error {
+		x.profile = profile
+		return nil
+	}
+}
+
 // New will construct a new bucket
 // info struct.
 func New(opts ...Option) (*Bucket, error) {
@@ -88,8 +150,12 @@ func New(opts ...Option) (*Bucket, error) {
 	return b, nil
 }
 
-// Check will check the bucket details and
-// AWS authentication are provided.
+// Check will check the bucket details are
+// provided.
+//
+// Credentials are not checked here as they
+// are resolved by the AWS credential chain
+// when the session is used.
 func (b *Bucket) Check() error {
 
 	// check for required info
@@ -99,15 +165,8 @@ func (b *Bucket) Check() error {
 	if len(b.region) == 0 {
 		return errBucketRegion
 	}
-
-	// get env variables
-	b.accessKeyID = os.Getenv(AccessKeyID)
-	b.accessSecretKey = os.Getenv(AccessSecretKey)
-	if len(b.accessKeyID) == 0 {
-		return errAccessKeyID
-	}
-	if len(b.accessSecretKey) == 0 {
-		return errAccessSecretKey
+	if len(b.endpoint) != 0 {
+		return checkEndpoint(b.endpoint)
 	}
 	return nil
 }
@@ -115,27 +174,164 @@ func (b *Bucket) Check() error {
 // Upload will upload the contents of a reader
 // to an S3 bucket using the provided key.
 // It returns the upload location and any error.
-func (b *Bucket) Upload(reader io.Reader, key string) (string, error) {
+// If the context is cancelled before the upload
+// completes, any multipart upload is aborted.
+func (b *Bucket) Upload(ctx context.Context, reader io.Reader, key string) (string, error) {
 
-	// check the bucket details
-	if err := b.Check(); err != nil {
+	// get an AWS session
+	sess, err := b.newSession()
+	if err != nil {
 		return "", err
 	}
 
-	// Initialize a session in us-west-2 that the SDK will use to load
-	// credentials from the shared credentials file ~/.aws/credentials.
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(b.region)},
-	)
-
 	uploader := s3manager.NewUploader(sess)
-	result, err := uploader.Upload(&s3manager.UploadInput{
+	result, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
 		Body:   reader,
 		Bucket: aws.String(b.name),
-		Key:    aws.String(key),
+		Key:    aws.String(b.key(key)),
 	})
 	if err != nil {
-		return "", fmt.Errorf("Failed to upload %v", err)
+		return "", fmt.Errorf("failed to upload: %w", err)
 	}
 	return result.Location, nil
 }
+
+// Exists will check if a key is in the S3 bucket.
+func (b *Bucket) Exists(ctx context.Context, key string) (bool, error) {
+	sess, err := b.newSession()
+	if err != nil {
+		return false, err
+	}
+	_, err = s3.New(sess).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(b.name),
+		Key:    aws.String(b.key(key)),
+	})
+	if err == nil {
+		return true, nil
+	}
+	var rerr awserr.RequestFailure
+	if errors.As(err, &rerr) && rerr.StatusCode() == 404 {
+		return false, nil
+	}
+	return false, err
+}
+
+// Delete will remove a key from the S3 bucket.
+func (b *Bucket) Delete(ctx context.Context, key string) error {
+	sess, err := b.newSession()
+	if err != nil {
+		return err
+	}
+	_, err = s3.New(sess).DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.name),
+		Key:    aws.String(b.key(key)),
+	})
+	return err
+}
+
+// Location returns the S3 URL for a key.
+func (b *Bucket) Location(key string) string {
+	return fmt.Sprintf("s3://%s/%s", b.name, b.key(key))
+}
+
+// key returns the full object key, including
+// any bucket prefix.
+func (b *Bucket) key(key string) string {
+	if len(b.prefix) == 0 {
+		return key
+	}
+	return path.Join(b.prefix, key)
+}
+
+// newSession checks the bucket details and
+// returns an AWS session for the bucket region
+// and endpoint.
+func (b *Bucket) newSession() (*session.Session, error) {
+	if err := b.Check(); err != nil {
+		return nil, err
+	}
+	config := aws.NewConfig().
+		WithRegion(b.region).
+		WithS3ForcePathStyle(b.pathStyle)
+	if len(b.endpoint) != 0 {
+		config = config.WithEndpoint(b.endpoint)
+	}
+	if b.skipVerify {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		config = config.WithHTTPClient(&http.Client{Transport: transport})
+	}
+	return session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		Profile:           b.profile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+}
+
+// checkEndpoint makes sure an endpoint is
+// an http(s) URL.
+func checkEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("could not parse endpoint (%v): %w", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("endpoint must be an http(s) URL: %v", endpoint)
+	}
+	return nil
+}
+
+// IsRetryable will classify an upload error as either
+// retryable (e.g. network problems, throttling or S3
+// server errors) or fatal (e.g. missing bucket details,
+// bad credentials or access denied).
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// TLS certificate problems won't fix themselves
+	if isCertificateError(err) {
+		return false
+	}
+
+	// check AWS errors, which include the failed multipart uploads
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		if aerr.Code() == "NoCredentialProviders" || request.IsErrorExpiredCreds(aerr) {
+			return false
+		}
+		var rerr awserr.RequestFailure
+		if errors.As(err, &rerr) && rerr.StatusCode() >= 500 {
+			return true
+		}
+		return request.IsErrorThrottle(aerr) || request.IsErrorRetryable(aerr)
+	}
+
+	// check for network errors outside of the AWS SDK
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isCertificateError checks an error chain, including
+// the original errors held by AWS errors, for a TLS
+// certificate verification error.
+func isCertificateError(err error) bool {
+	for err != nil {
+		var authErr x509.UnknownAuthorityError
+		var certErr x509.CertificateInvalidError
+		var hostErr x509.HostnameError
+		if errors.As(err, &authErr) || errors.As(err, &certErr) || errors.As(err, &hostErr) {
+			return true
+		}
+		if aerr, ok := err.(awserr.Error); ok {
+			err = aerr.OrigErr()
+			continue
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
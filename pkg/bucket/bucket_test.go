@@ -1,7 +1,19 @@
 package bucket
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
 )
 
 // TestBucket
@@ -11,3 +23,132 @@ func TestBucket(t *testing.T) {
 		t.Log(err)
 	}
 }
+
+// TestIsRetryable
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{errBucketName, false},
+		{fmt.Errorf("failed to upload: %w", awserr.New("NoCredentialProviders", "no valid providers in chain", nil)), false},
+		{awserr.NewRequestFailure(awserr.New("AccessDenied", "access denied", nil), 403, "id"), false},
+		{awserr.NewRequestFailure(awserr.New("InternalError", "internal error", nil), 500, "id"), true},
+		{awserr.NewRequestFailure(awserr.New("SlowDown", "reduce your request rate", nil), 503, "id"), true},
+		{awserr.New("Throttling", "rate exceeded", nil), true},
+		{fmt.Errorf("failed to upload: %w", io.ErrUnexpectedEOF), true},
+		{errors.New("unknown"), false},
+	}
+	for i, test := range tests {
+		if IsRetryable(test.err) != test.retryable {
+			t.Fatalf("test %d: wanted retryable == %v for %v", i, test.retryable, test.err)
+		}
+	}
+}
+
+// TestNewStorage
+func TestNewStorage(t *testing.T) {
+	storage, err := NewStorage("s3://name/some/prefix", SetRegion("eu-west-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc := storage.Location("sample.fastq.gz"); loc != "s3://name/some/prefix/sample.fastq.gz" {
+		t.Fatalf("unexpected location: %v", loc)
+	}
+	storage, err = NewStorage("file:///data/out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc := storage.Location("sample.fastq.gz"); loc != "file:///data/out/sample.fastq.gz" {
+		t.Fatalf("unexpected location: %v", loc)
+	}
+	for _, storageURL := range []string{"s3://", "file://remote/data", "gs://name"} {
+		if _, err := NewStorage(storageURL); err == nil {
+			t.Fatalf("expected error for storage URL: %v", storageURL)
+		}
+	}
+}
+
+// fakeS3 is a minimal path-style S3 stand-in that
+// supports the object requests used by Bucket.
+type fakeS3 struct {
+	sync.Mutex
+	objects map[string][]byte
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		f.objects[r.URL.Path] = data
+	case http.MethodHead:
+		if _, ok := f.objects[r.URL.Path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// TestCustomEndpoint
+func TestCustomEndpoint(t *testing.T) {
+	for _, key := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"} {
+		defer os.Setenv(key, os.Getenv(key))
+		os.Setenv(key, "test")
+	}
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+
+	// the bad endpoints should be caught
+	for _, endpoint := range []string{"minio.local:9000", "ftp://minio.local"} {
+		if _, err := New(SetName("name"), SetEndpoint(endpoint)); err == nil {
+			t.Fatalf("expected error for endpoint: %v", endpoint)
+		}
+	}
+
+	// the test server uses a self-signed certificate
+	ctx := context.Background()
+	b, err := New(SetName("name"), SetPrefix("runs"), SetEndpoint(server.URL), SetPathStyle(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = b.Upload(ctx, strings.NewReader("reads"), "sample.fastq.gz")
+	if err == nil {
+		t.Fatal("expected TLS verification to fail")
+	}
+	if IsRetryable(err) {
+		t.Fatalf("TLS verification error should not be retried: %v", err)
+	}
+	if err := SetSkipVerify(true)(b); err != nil {
+		t.Fatal(err)
+	}
+
+	// upload, check and delete an object
+	if _, err := b.Upload(ctx, strings.NewReader("reads"), "sample.fastq.gz"); err != nil {
+		t.Fatal(err)
+	}
+	if data := string(fake.objects["/name/runs/sample.fastq.gz"]); data != "reads" {
+		t.Fatalf("unexpected object contents: %q", data)
+	}
+	if ok, err := b.Exists(ctx, "sample.fastq.gz"); err != nil || !ok {
+		t.Fatalf("uploaded object not found: %v", err)
+	}
+	if err := b.Delete(ctx, "sample.fastq.gz"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := b.Exists(ctx, "sample.fastq.gz"); err != nil || ok {
+		t.Fatalf("deleted object still found: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var (
+	errLocalRoot = errors.New("local storage directory is required")
+)
+
+// Local is a Storage backend that writes
+// to a directory on the local filesystem.
+type Local struct {
+	root string
+}
+
+// NewLocal will construct a Local storage backend
+// that writes to the provided directory.
+func NewLocal(root string) (*Local, error) {
+	if len(root) == 0 {
+		return nil, errLocalRoot
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	return &Local{root: root}, nil
+}
+
+// Check will create the storage directory if
+// needed and make sure it is a directory.
+func (l *Local) Check() error {
+	if len(l.root) == 0 {
+		return errLocalRoot
+	}
+	if err := os.MkdirAll(l.root, 0755); err != nil {
+		return err
+	}
+	info, err := os.Stat(l.root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("local storage is not a directory: %v", l.root)
+	}
+	return nil
+}
+
+// Upload will write the contents of a reader to
+// a file in the storage directory.
+//
+// The data is written to a temporary file which
+// is only moved into place once the reader is
+// finished, so a failed or cancelled upload
+// won't leave a partial file behind.
+func (l *Local) Upload(ctx context.Context, reader io.Reader, key string) (string, error) {
+	if err := l.Check(); err != nil {
+		return "", err
+	}
+	dest := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(dest), ".archer-upload-")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(tmp, &contextReader{ctx: ctx, reader: reader}); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to upload: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return l.Location(key), nil
+}
+
+// Exists will check if a key is in the storage directory.
+func (l *Local) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := os.Stat(l.path(key))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// Delete will remove a key from the storage directory.
+// Deleting a missing key is not an error.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(l.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Location returns the file URL for a key.
+func (l *Local) Location(key string) string {
+	return "file://" + filepath.ToSlash(l.path(key))
+}
+
+// path returns the filesystem path for a key.
+func (l *Local) path(key string) string {
+	return filepath.Join(l.root, filepath.FromSlash(filepath.Clean("/"+key)))
+}
+
+// contextReader wraps a reader so that reads
+// stop once a context is cancelled.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+// Read implements the io.Reader interface.
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.reader.Read(p)
+}
@@ -0,0 +1,83 @@
+package bucket
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLocal
+func TestLocal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archer-local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	storage, err := NewStorage("file://" + filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Check(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// upload a file and check it can be found
+	location, err := storage.Upload(ctx, strings.NewReader("reads"), "sample.fastq.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location != storage.Location("sample.fastq.gz") {
+		t.Fatalf("unexpected upload location: %v", location)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "out", "sample.fastq.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "reads" {
+		t.Fatalf("unexpected upload contents: %v", string(data))
+	}
+	if ok, err := storage.Exists(ctx, "sample.fastq.gz"); err != nil || !ok {
+		t.Fatalf("uploaded file not found: %v", err)
+	}
+
+	// keys can't escape the storage directory
+	if _, err := storage.Upload(ctx, strings.NewReader("reads"), "../escape"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape")); !os.IsNotExist(err) {
+		t.Fatal("upload escaped the storage directory")
+	}
+
+	// delete the file, twice
+	for i := 0; i < 2; i++ {
+		if err := storage.Delete(ctx, "sample.fastq.gz"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ok, _ := storage.Exists(ctx, "sample.fastq.gz"); ok {
+		t.Fatal("deleted file still exists")
+	}
+
+	// a cancelled upload should not leave a file behind
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	if _, err := storage.Upload(cctx, reader, "cancelled.fastq.gz"); err == nil {
+		t.Fatal("cancelled upload did not return an error")
+	}
+	files, err := ioutil.ReadDir(filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if file.Name() != "escape" {
+			t.Fatalf("unexpected file left in storage: %v", file.Name())
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Storage is the interface used by Archer to
+// store the filtered reads for a sample.
+type Storage interface {
+
+	// Check will check the storage details are complete
+	Check() error
+
+	// Upload will write the contents of a reader to the
+	// storage using the provided key. It returns the
+	// upload location and any error.
+	Upload(ctx context.Context, reader io.Reader, key string) (string, error)
+
+	// Exists will check if a key is in the storage.
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// Delete will remove a key from the storage.
+	Delete(ctx context.Context, key string) error
+
+	// Location returns the URL for a key in the storage.
+	Location(key string) string
+}
+
+// NewStorage will return the Storage for a URL. The URL
+// scheme selects the backend:
+//
+//	s3://bucket/prefix	upload to an AWS S3 bucket
+//	file:///data/out	write to a local directory
+//
+// Any options are used to configure an S3 backend.
+func NewStorage(storageURL string, opts ...Option) (Storage, error) {
+	u, err := url.Parse(storageURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse storage URL (%v): %w", storageURL, err)
+	}
+	switch u.Scheme {
+	case "s3":
+		opts = append([]Option{SetName(u.Host), SetPrefix(strings.TrimPrefix(u.Path, "/"))}, opts...)
+		return New(opts...)
+	case "file":
+		if len(u.Host) != 0 && u.Host != "localhost" {
+			return nil, fmt.Errorf("file storage URL must be a local path: %v", storageURL)
+		}
+		return NewLocal(u.Path)
+	default:
+		return nil, fmt.Errorf("unsupported storage URL scheme (%v), must be s3:// or file://", u.Scheme)
+	}
+}
+
+// check the backends implement the Storage interface
+var (
+	_ Storage = (*Bucket)(nil)
+	_ Storage = (*Local)(nil)
+)
@@ -0,0 +1,265 @@
+// Package host builds and screens reads against a host (e.g. human)
+// k-mer index, so that host reads can be removed before upload.
+package host
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/will-rowe/nthash"
+
+	"github.com/will-rowe/archer/pkg/minhash"
+)
+
+const (
+
+	// DefaultKmerSize is the default k-mer size for a host index
+	DefaultKmerSize = 21
+
+	// DefaultScaled is the default scale factor for a host index, keeping roughly 1 in every 20 k-mers
+	DefaultScaled = 20
+
+	// DefaultThreshold is the default fraction of a read's sampled k-mers found in the host index to call it a host read
+	DefaultThreshold = 0.5
+
+	// maxKmerSize is the largest k-mer size allowed
+	maxKmerSize = 32
+
+	// chunkSize is the number of bases hashed at a time when building an index
+	chunkSize = 1 << 20
+
+	// readChunkSize is the number of hashes read at a time when loading an index
+	readChunkSize = 1 << 20
+
+	// indexVersion is the version of the index file format
+	indexVersion = 1
+)
+
+var (
+
+	// indexMagic starts each index file
+	indexMagic = [8]byte{'A', 'R', 'C', 'H', 'H', 'O', 'S', 'T'}
+
+	// ErrBadIndex is returned when a file is not a host index
+	ErrBadIndex = errors.New("file is not an archer host index")
+)
+
+// Index is a set of sampled k-mer hashes from a
+// host genome. The k-mers are canonical and are
+// sampled with a FracMinHash, so that the same
+// k-mers are sampled from reads and the host.
+type Index struct {
+	kmerSize int
+	scaled   uint64
+	hashes   []uint64 // sorted and unique
+}
+
+// indexHeader is written at the start of
+// an index file.
+type indexHeader struct {
+	Magic     [8]byte
+	Version   uint32
+	KmerSize  uint32
+	Scaled    uint64
+	NumHashes uint64
+}
+
+// Build will build a host index from the sequences in
+// a FASTA reader. Only runs of unambiguous bases (ACGT)
+// are hashed, so N's and other IUPAC codes are skipped.
+func Build(fasta io.Reader, kmerSize int, scaled uint64) (*Index, error) {
+	if kmerSize < 1 || kmerSize > maxKmerSize {
+		return nil, fmt.Errorf("k-mer size must be between 1 and %d", maxKmerSize)
+	}
+	if scaled < 1 {
+		return nil, errors.New("scale factor must be greater than 0")
+	}
+	sketcher := minhash.NewFracMinHash(kmerSize, scaled)
+
+	// collect runs of bases from each sequence, hashing in chunks
+	reader := bufio.NewReader(fasta)
+	run := make([]byte, 0, chunkSize)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) != 0 && line[0] == '>' {
+			run = addRun(sketcher, run, kmerSize, true)
+		} else {
+			for _, base := range line {
+				switch base {
+				case 'A', 'C', 'G', 'T':
+					run = append(run, base)
+				case 'a', 'c', 'g', 't':
+					run = append(run, base-32)
+				case '\n', '\r':
+				default:
+					run = addRun(sketcher, run, kmerSize, true)
+				}
+			}
+			if len(run) >= chunkSize {
+				run = addRun(sketcher, run, kmerSize, false)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	addRun(sketcher, run, kmerSize, true)
+	return &Index{
+		kmerSize: kmerSize,
+		scaled:   scaled,
+		hashes:   sketcher.GetHashes(),
+	}, nil
+}
+
+// addRun will hash the k-mers in a run of bases and add
+// them to the sketcher. If the run has ended, an empty
+// run is returned, otherwise the last k-1 bases are kept
+// so that the run can continue.
+func addRun(sketcher *minhash.FracMinHash, run []byte, kmerSize int, ended bool) []byte {
+	if len(run) >= kmerSize {
+		hasher, err := nthash.NewHasher(&run, uint(kmerSize))
+		if err == nil {
+			sketcher.Add(hasher.Hash(true))
+		}
+	}
+	if ended {
+		return run[:0]
+	}
+	if len(run) < kmerSize {
+		return run
+	}
+	return append(run[:0], run[len(run)-kmerSize+1:]...)
+}
+
+// Load will read a host index from a file.
+func Load(path string) (*Index, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	return Read(bufio.NewReader(fh))
+}
+
+// Read will read a host index.
+func Read(r io.Reader) (*Index, error) {
+	var header indexHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrBadIndex
+		}
+		return nil, err
+	}
+	if header.Magic != indexMagic {
+		return nil, ErrBadIndex
+	}
+	if header.Version != indexVersion {
+		return nil, fmt.Errorf("unsupported host index version: %d", header.Version)
+	}
+	if header.KmerSize < 1 || header.KmerSize > maxKmerSize || header.Scaled < 1 {
+		return nil, ErrBadIndex
+	}
+
+	// read the hashes a chunk at a time, so that a corrupt hash count
+	// can't make us allocate more than the file actually holds
+	hashes := []uint64{}
+	for remaining := header.NumHashes; remaining > 0; {
+		n := remaining
+		if n > readChunkSize {
+			n = readChunkSize
+		}
+		chunk := make([]uint64, n)
+		if err := binary.Read(r, binary.LittleEndian, chunk); err != nil {
+			return nil, fmt.Errorf("could not read host index hashes: %w", err)
+		}
+		hashes = append(hashes, chunk...)
+		remaining -= n
+	}
+	if !sort.SliceIsSorted(hashes, func(i, j int) bool { return hashes[i] < hashes[j] }) {
+		return nil, ErrBadIndex
+	}
+	return &Index{
+		kmerSize: int(header.KmerSize),
+		scaled:   header.Scaled,
+		hashes:   hashes,
+	}, nil
+}
+
+// Write will write the host index.
+func (idx *Index) Write(w io.Writer) error {
+	header := indexHeader{
+		Magic:     indexMagic,
+		Version:   indexVersion,
+		KmerSize:  uint32(idx.kmerSize),
+		Scaled:    idx.scaled,
+		NumHashes: uint64(len(idx.hashes)),
+	}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, idx.hashes)
+}
+
+// GetKmerSize returns the k-mer size of the index.
+func (idx *Index) GetKmerSize() int {
+	return idx.kmerSize
+}
+
+// GetScaled returns the scale factor of the index.
+func (idx *Index) GetScaled() uint64 {
+	return idx.scaled
+}
+
+// GetNumHashes returns the number of k-mer hashes
+// in the index.
+func (idx *Index) GetNumHashes() int {
+	return len(idx.hashes)
+}
+
+// Screen will sample the k-mers of a read in the same way
+// as the host index and return the fraction of them found
+// in the index, plus the number sampled. Reads with no
+// sampled k-mers score 0.
+func (idx *Index) Screen(read []byte) (float64, int) {
+	sketcher := minhash.NewFracMinHash(idx.kmerSize, idx.scaled)
+	run := make([]byte, 0, len(read))
+	for _, base := range read {
+		switch base {
+		case 'A', 'C', 'G', 'T':
+			run = append(run, base)
+		case 'a', 'c', 'g', 't':
+			run = append(run, base-32)
+		default:
+			run = addRun(sketcher, run, idx.kmerSize, true)
+		}
+	}
+	addRun(sketcher, run, idx.kmerSize, true)
+	sampled := sketcher.GetHashes()
+	if len(sampled) == 0 {
+		return 0.0, 0
+	}
+	hits := 0
+	for _, hash := range sampled {
+		i := sort.Search(len(idx.hashes), func(i int) bool { return idx.hashes[i] >= hash })
+		if i < len(idx.hashes) && idx.hashes[i] == hash {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(sampled)), len(sampled)
+}
+
+// IsHost will screen a read against the host index and
+// return true if the fraction of its sampled k-mers in
+// the index is at least the threshold.
+func (idx *Index) IsHost(read []byte, threshold float64) bool {
+	score, sampled := idx.Screen(read)
+	return sampled != 0 && score >= threshold
+}
@@ -0,0 +1,113 @@
+package host
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randomSeq returns a random DNA sequence.
+func randomSeq(r *rand.Rand, length int) []byte {
+	seq := make([]byte, length)
+	for i := range seq {
+		seq[i] = "ACGT"[r.Intn(4)]
+	}
+	return seq
+}
+
+// TestIndex will check a host index can be built,
+// written, read and used to screen reads.
+func TestIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	chr1, chr2, other := randomSeq(r, 20000), randomSeq(r, 5000), randomSeq(r, 400)
+
+	// write the host as a FASTA, with wrapped lines, lower case bases and N's
+	var fasta strings.Builder
+	fasta.WriteString(">chr1\n")
+	for i := 0; i < len(chr1); i += 60 {
+		end := i + 60
+		if end > len(chr1) {
+			end = len(chr1)
+		}
+		fasta.Write(chr1[i:end])
+		fasta.WriteString("\n")
+	}
+	fasta.WriteString(">chr2 soft masked\r\n" + strings.ToLower(string(chr2)) + "NNNNNNNNNN\r\n")
+	idx, err := Build(strings.NewReader(fasta.String()), DefaultKmerSize, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.GetNumHashes() == 0 {
+		t.Fatal("no hashes in host index")
+	}
+
+	// check the index can be written and read back
+	var buf bytes.Buffer
+	if err := idx.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.GetKmerSize() != DefaultKmerSize || loaded.GetScaled() != 4 || loaded.GetNumHashes() != idx.GetNumHashes() {
+		t.Fatalf("loaded index does not match: %d/%d/%d", loaded.GetKmerSize(), loaded.GetScaled(), loaded.GetNumHashes())
+	}
+	if _, err := Read(strings.NewReader("not an index")); err != ErrBadIndex {
+		t.Fatalf("expected bad index error, got %v", err)
+	}
+
+	// a truncated index, or one with a corrupt hash count, should fail
+	var written bytes.Buffer
+	if err := idx.Write(&written); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(bytes.NewReader(written.Bytes()[:written.Len()-4])); err == nil {
+		t.Fatal("expected truncated index to fail")
+	}
+	corrupt := append([]byte{}, written.Bytes()...)
+	binary.LittleEndian.PutUint64(corrupt[24:32], math.MaxUint64)
+	if _, err := Read(bytes.NewReader(corrupt)); err == nil {
+		t.Fatal("expected corrupt hash count to fail")
+	}
+
+	// screen reads, including a chimera that is mostly not host
+	mixed := append(append([]byte{}, chr1[1000:1100]...), other[:300]...)
+	tests := []struct {
+		read []byte
+		host bool
+	}{
+		{chr1[5000:5400], true},
+		{chr2[1000:1400], true},
+		{bytes.ToLower(chr1[100:500]), true},
+		{other, false},
+		{mixed, false},
+		{[]byte("NNNNNNNNNNNNNNNNNNNNNNNNN"), false},
+	}
+	for i, test := range tests {
+		if isHost := loaded.IsHost(test.read, DefaultThreshold); isHost != test.host {
+			score, sampled := loaded.Screen(test.read)
+			t.Fatalf("test %d: wanted host %v, got %v (%.2f of %d k-mers)", i, test.host, isHost, score, sampled)
+		}
+	}
+	if _, err := Build(strings.NewReader(fasta.String()), 0, 4); err == nil {
+		t.Fatal("expected bad k-mer size to fail")
+	}
+}
+
+// TestBuildChunks will check that k-mers spanning
+// the chunks of a long sequence are hashed.
+func TestBuildChunks(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	seq := randomSeq(r, chunkSize+1000)
+	idx, err := Build(bytes.NewReader(append([]byte(">chr\n"), seq...)), DefaultKmerSize, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.GetNumHashes() != len(seq)-DefaultKmerSize+1 {
+		t.Fatalf("wanted %d hashes, got %d", len(seq)-DefaultKmerSize+1, idx.GetNumHashes())
+	}
+}
@@ -111,3 +111,47 @@ func (mh *MinHash) GetDistance(query *MinHash) (float64, error) {
 	}
 	return intersect / float64(maxLen), nil
 }
+
+// GetContainment returns the fraction of the k-mers
+// in the minhash object that are contained in the
+// reference sketch.
+//
+// Only sketch values up to the largest value in the
+// reference sketch are compared, as any larger values
+// could be missing from the reference sketch even if
+// the k-mer is present in the reference. This means
+// that a short query from part of a longer reference
+// can still score highly.
+func (mh *MinHash) GetContainment(reference *MinHash) (float64, error) {
+	if mh.kSize != reference.kSize {
+		return 0.0, fmt.Errorf("kmer sizes do not match: got %d and %d", mh.kSize, reference.kSize)
+	}
+	if mh.sketchSize != reference.sketchSize {
+		return 0.0, fmt.Errorf("sketch sizes do not match: got %d and %d", mh.sketchSize, reference.sketchSize)
+	}
+	if len(*reference.sketch) == 0 {
+		return 0.0, nil
+	}
+
+	// the reference sketch is a max-heap, so the largest value is first
+	maxVal := (*reference.sketch)[0]
+	minimums := make(map[uint64]float64, len(*reference.sketch))
+	for _, val := range *reference.sketch {
+		minimums[val]++
+	}
+	intersect, total := 0.0, 0.0
+	for _, val := range *mh.sketch {
+		if val > maxVal {
+			continue
+		}
+		total++
+		if count, ok := minimums[val]; ok && count > 0 {
+			intersect++
+			minimums[val] = count - 1
+		}
+	}
+	if total == 0 {
+		return 0.0, nil
+	}
+	return intersect / total, nil
+}
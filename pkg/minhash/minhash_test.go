@@ -1,6 +1,7 @@
 package minhash
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -72,3 +73,112 @@ func TestSimilarity(t *testing.T) {
 	}
 
 }
+
+// TestContainment
+func TestContainment(t *testing.T) {
+	mh1 := New(kmerSize, sketchSize)
+	addValues(mh1, sketchSize*2)
+
+	// test kmer size and sketch size catch
+	if _, err := mh1.GetContainment(New(kmerSize+1, sketchSize)); err == nil {
+		t.Fatal("missed k-mer size mismatch")
+	}
+	if _, err := mh1.GetContainment(New(kmerSize, sketchSize+1)); err == nil {
+		t.Fatal("missed sketch size mismatch")
+	}
+
+	// a partial query is fully contained, but isn't similar
+	mh2 := New(kmerSize, sketchSize)
+	addValues(mh2, sketchSize/2)
+	containment, err := mh2.GetContainment(mh1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if containment != 1.0 {
+		t.Fatalf("incorrect containment: expected 1.0, got %f", containment)
+	}
+	dist, err := mh2.GetDistance(mh1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dist != 0.5 {
+		t.Fatalf("incorrect distance: expected 0.5, got %f", dist)
+	}
+
+	// the larger sketch is only compared up to the largest value in
+	// the smaller one, and all of its values up to there are shared
+	containment, err = mh1.GetContainment(mh2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if containment != 1.0 {
+		t.Fatalf("incorrect containment: expected 1.0 for values up to the reference maximum, got %f", containment)
+	}
+
+	// test a half contained query
+	mh3 := New(kmerSize, sketchSize)
+	valChan := make(chan uint64)
+	go func() {
+		for i := uint64(0); i < uint64(sketchSize); i++ {
+			valChan <- i * 2
+		}
+		close(valChan)
+	}()
+	mh3.Add(valChan)
+	containment, err = mh1.GetContainment(mh3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if containment != 0.5 {
+		t.Fatalf("incorrect containment: expected 0.5, got %f", containment)
+	}
+}
+
+// TestFracMinHash
+func TestFracMinHash(t *testing.T) {
+	fmh := NewFracMinHash(kmerSize, 4)
+	maxHash := GetMaxHash(4)
+	valChan := make(chan uint64)
+	go func() {
+		for _, val := range []uint64{maxHash + 1, 7, 3, maxHash, 7, 3, 1} {
+			valChan <- val
+		}
+		close(valChan)
+	}()
+	fmh.Add(valChan)
+	hashes := fmh.GetHashes()
+	wanted := []uint64{1, 3, 7, maxHash}
+	if len(hashes) != len(wanted) {
+		t.Fatalf("wanted hashes %v, got %v", wanted, hashes)
+	}
+	for i := range wanted {
+		if hashes[i] != wanted[i] {
+			t.Fatalf("wanted hashes %v, got %v", wanted, hashes)
+		}
+	}
+}
+
+// TestFracMinHashCompact will check that batches of new
+// hashes are merged into the compacted hashes without
+// losing or duplicating any.
+func TestFracMinHashCompact(t *testing.T) {
+	fmh := NewFracMinHash(kmerSize, 1)
+	rng := rand.New(rand.NewSource(1))
+	seen := make(map[uint64]struct{})
+	for batch := 0; batch < 10; batch++ {
+		for i := 0; i < 1000; i++ {
+			hash := uint64(rng.Intn(5000))
+			fmh.hashes = append(fmh.hashes, hash)
+			seen[hash] = struct{}{}
+		}
+		fmh.compact()
+		if fmh.compacted != len(fmh.hashes) || len(fmh.hashes) != len(seen) {
+			t.Fatalf("batch %d: wanted %d unique hashes, got %d", batch, len(seen), len(fmh.hashes))
+		}
+		for i := 1; i < len(fmh.hashes); i++ {
+			if fmh.hashes[i] <= fmh.hashes[i-1] {
+				t.Fatalf("batch %d: hashes are not sorted and unique at %d", batch, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package minhash
+
+import (
+	"math"
+	"sort"
+)
+
+// compactSize is the minimum number of new hashes a
+// FracMinHash holds before removing duplicates. Once
+// more unique hashes are held, the new hashes can grow
+// to match them, so that large sketches (e.g. a host
+// genome) aren't merged too often.
+const compactSize = 1 << 20
+
+// FracMinHash keeps every hashed k-mer below a
+// maximum hash value, rather than a fixed number
+// of minimums. Roughly 1 in every scaled k-mers
+// is kept, so sketches of very different sized
+// sequences (e.g. a read and a host genome) can
+// be compared.
+type FracMinHash struct {
+	kSize     int
+	scaled    uint64
+	maxHash   uint64
+	hashes    []uint64
+	compacted int
+}
+
+// NewFracMinHash returns an initialised FracMinHash
+// object which is ready to receive hashed k-mers.
+func NewFracMinHash(kSize int, scaled uint64) *FracMinHash {
+	if scaled == 0 {
+		scaled = 1
+	}
+	return &FracMinHash{
+		kSize:   kSize,
+		scaled:  scaled,
+		maxHash: GetMaxHash(scaled),
+		hashes:  []uint64{},
+	}
+}
+
+// GetMaxHash returns the largest hash value kept
+// for a scale factor.
+func GetMaxHash(scaled uint64) uint64 {
+	if scaled <= 1 {
+		return math.MaxUint64
+	}
+	return math.MaxUint64 / scaled
+}
+
+// Add will check k-mers and keep any below
+// the maximum hash value.
+func (fmh *FracMinHash) Add(kmerChan <-chan uint64) {
+	for kmer := range kmerChan {
+		if kmer > fmh.maxHash {
+			continue
+		}
+		fmh.hashes = append(fmh.hashes, kmer)
+		if newHashes := len(fmh.hashes) - fmh.compacted; newHashes > compactSize && newHashes > fmh.compacted {
+			fmh.compact()
+		}
+	}
+}
+
+// GetHashes returns the sorted, unique hashes
+// kept by the FracMinHash object.
+func (fmh *FracMinHash) GetHashes() []uint64 {
+	fmh.compact()
+	hashes := make([]uint64, len(fmh.hashes))
+	copy(hashes, fmh.hashes)
+	return hashes
+}
+
+// GetKmerSize returns the k-mer size.
+func (fmh *FracMinHash) GetKmerSize() int {
+	return fmh.kSize
+}
+
+// GetScaled returns the scale factor.
+func (fmh *FracMinHash) GetScaled() uint64 {
+	return fmh.scaled
+}
+
+// compact will sort the new hashes, remove any
+// duplicates and merge them into the already
+// compacted hashes.
+func (fmh *FracMinHash) compact() {
+	if fmh.compacted == len(fmh.hashes) {
+		return
+	}
+
+	// sort and dedupe the new hashes, keeping a copy to merge from
+	batch := fmh.hashes[fmh.compacted:]
+	sort.Slice(batch, func(i, j int) bool { return batch[i] < batch[j] })
+	newHashes := make([]uint64, 0, len(batch))
+	for i, hash := range batch {
+		if i == 0 || hash != batch[i-1] {
+			newHashes = append(newHashes, hash)
+		}
+	}
+
+	// merge from the back, so that compacted hashes are never overwritten before they are read
+	i, j, k := fmh.compacted-1, len(newHashes)-1, len(fmh.hashes)
+	for i >= 0 || j >= 0 {
+		var hash uint64
+		switch {
+		case j < 0 || (i >= 0 && fmh.hashes[i] > newHashes[j]):
+			hash = fmh.hashes[i]
+			i--
+		case i < 0 || newHashes[j] > fmh.hashes[i]:
+			hash = newHashes[j]
+			j--
+		default:
+			hash = newHashes[j]
+			i--
+			j--
+		}
+		k--
+		fmh.hashes[k] = hash
+	}
+	unique := copy(fmh.hashes, fmh.hashes[k:])
+	fmh.hashes = fmh.hashes[:unique]
+	fmh.compacted = unique
+}
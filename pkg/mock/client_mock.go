@@ -56,6 +56,26 @@ func (mr *MockArcherClientMockRecorder) Cancel(arg0, arg1 interface{}, arg2 ...i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockArcherClient)(nil).Cancel), varargs...)
 }
 
+// GetInfo mocks base method.
+func (m *MockArcherClient) GetInfo(arg0 context.Context, arg1 *v1.GetInfoRequest, arg2 ...grpc.CallOption) (v1.Archer_GetInfoClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetInfo", varargs...)
+	ret0, _ := ret[0].(v1.Archer_GetInfoClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInfo indicates an expected call of GetInfo.
+func (mr *MockArcherClientMockRecorder) GetInfo(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockArcherClient)(nil).GetInfo), varargs...)
+}
+
 // Process mocks base method.
 func (m *MockArcherClient) Process(arg0 context.Context, arg1 *v1.ProcessRequest, arg2 ...grpc.CallOption) (*v1.ProcessResponse, error) {
 	m.ctrl.T.Helper()
@@ -2,12 +2,17 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/prologic/bitcask"
+	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -15,6 +20,7 @@ import (
 	"github.com/will-rowe/archer/pkg/amplicons"
 	api "github.com/will-rowe/archer/pkg/api/v1"
 	"github.com/will-rowe/archer/pkg/bucket"
+	"github.com/will-rowe/archer/pkg/host"
 )
 
 // useSync will run sync on every bit cask transaction, improving stability at the expense of time
@@ -23,11 +29,14 @@ const useSync = true
 // apiVersion sets the API version to use
 const apiVersion = "1"
 
-// lengthThreshold is the percentage above/below the mean amplicon size to set max/min length read filtering to
-const lengthThreshold = 0.2
+// DefaultProgressInterval is the time between progress updates for a running sample
+const DefaultProgressInterval = 5 * time.Second
 
-// jaccardThreshold is the minimum jaccard distance to match a read to an amplicon
-const jaccardThreshold = 0.7
+// defaultPageSize is the number of samples to send in each GetInfo response if the request does not set one
+const defaultPageSize = 100
+
+// schemeCacheDir is the directory in the db path used to cache downloaded primer schemes
+const schemeCacheDir = "schemes"
 
 // ArcherOption is a wrapper struct used to pass functional
 // options to the Archer constructor.
@@ -48,24 +57,70 @@ type Archer struct {
 
 	// numWorkers sets the number of process request workers to use
 	numWorkers int
+	// maxRetries sets the number of times a sample is retried after a transient upload error
+	maxRetries int
+	// progressInterval sets the time between progress updates for a running sample
+	progressInterval time.Duration
 
 	// db is a key-value store for recording sample info
 	db *bitcask.Bitcask
 
-	// bucket contains the S3 info for managing uploads
-	bucket *bucket.Bucket
-
+	// storage is where the filtered reads are uploaded to
+	storage bucket.Storage
+
+	// manifestURL is where to collect the ARTIC primer scheme manifest from
+	manifestURL string
+	// schemesRoot is an optional local copy of the primer-schemes repository
+	schemesRoot string
+	// schemeCache is where downloaded primer scheme files are cached
+	schemeCache string
+	// sketchParams are the default k-mer size, sketch size and canonical mode for sketching
+	sketchParams amplicons.SketchParams
+	// filters are the default read filters
+	filters filterDefaults
+	// hostIndex is an optional host k-mer index used to remove host reads
+	hostIndex *host.Index
+	// hostThreshold is the fraction of sampled k-mers in the host index for a read to be removed
+	hostThreshold float32
+	// headerMapDir is where local files mapping scrubbed read headers to the originals are kept
+	headerMapDir string
+	// qcGate is checked before a sample is uploaded
+	qcGate qcGate
+	// stagingDir is where the reads for a sample are staged while the QC gate is checked
+	stagingDir string
 	// manifest is the ARTIC primer scheme manifest
 	manifest *api.Manifest
 
 	// ampliconCache is an in-memory cache of the schemes used for the current session
 	ampliconCache map[string]*amplicons.AmpliconSet
+	// cacheLock guards the ampliconCache
+	cacheLock sync.Mutex
+	// recoveryPolicy sets how samples interrupted by a service restart are handled
+	recoveryPolicy RecoveryPolicy
+	// retryErrors will re-queue errored samples when the service starts
+	retryErrors bool
 
 	// processChan is used to send incoming process requests to the process workers
 	processChan chan *api.SampleInfo
+	// quit is closed when the service shuts down, to stop any samples still being queued
+	quit chan struct{}
+	// queueing tracks the goroutines queueing samples, which must finish before processChan is closed
+	queueing sync.WaitGroup
+	// inFlight holds the queued and running samples, keyed by sample ID
+	inFlight map[string]*inFlightSample
+
+	// watchers broadcasts sample updates to any connected watchers
+	watchers *watcherHub
+}
 
-	// watcherChan sends updates to any connected watcher
-	watcherChan chan *api.SampleInfo
+// inFlightSample links a queued or running
+// sample to the function that cancels its
+// processing.
+type inFlightSample struct {
+	sample  *api.SampleInfo
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running bool
 }
 
 // SetNumWorkers is an option setter for the NewArcher
@@ -81,6 +136,56 @@ func SetNumWorkers(numWorkers int) ArcherOption {
 	}
 }
 
+// SetMaxRetries is an option setter for the NewArcher
+// constructor that sets the number of times a sample
+// will be retried after a transient upload error.
+func SetMaxRetries(maxRetries int) ArcherOption {
+	return func(x *Archer) error {
+		if maxRetries < 0 {
+			return errors.New("number of retries must be >= 0")
+		}
+		x.maxRetries = maxRetries
+		return nil
+	}
+}
+
+// SetProgressInterval is an option setter for the NewArcher
+// constructor that sets the time between progress updates
+// sent to watchers for each running sample.
+func SetProgressInterval(interval time.Duration) ArcherOption {
+	return func(x *Archer) error {
+		if interval < time.Second {
+			return errors.New("progress interval must be at least one second")
+		}
+		x.progressInterval = interval
+		return nil
+	}
+}
+
+// SetRecoveryPolicy is an option setter for the NewArcher
+// constructor that sets how samples left queued or running
+// by the last service run are handled (resume, restart or error).
+func SetRecoveryPolicy(policy string) ArcherOption {
+	return func(x *Archer) error {
+		p, err := ParseRecoveryPolicy(policy)
+		if err != nil {
+			return err
+		}
+		x.recoveryPolicy = p
+		return nil
+	}
+}
+
+// SetRetryErrors is an option setter for the NewArcher
+// constructor that will re-queue any errored samples
+// when the service starts.
+func SetRetryErrors(retry bool) ArcherOption {
+	return func(x *Archer) error {
+		x.retryErrors = retry
+		return nil
+	}
+}
+
 // SetDb is an option setter for the NewArcher constructor
 // that opens a db at the specified path and sets the
 // appropriate field of the Archer struct.
@@ -93,40 +198,336 @@ func SetDb(dbPath string) ArcherOption {
 			return err
 		}
 		x.db = db
+
+		// keep downloaded primer schemes and staged reads with the db unless told otherwise
+		if len(x.schemeCache) == 0 {
+			x.schemeCache = filepath.Join(dbPath, schemeCacheDir)
+		}
+		if len(x.stagingDir) == 0 {
+			x.stagingDir = filepath.Join(dbPath, stagingDir)
+		}
+		return nil
+	}
+}
+
+// SetSchemeCache is an option setter for the NewArcher
+// constructor that sets the directory used to cache
+// downloaded primer scheme files. By default, files are
+// cached alongside the database. An empty directory is
+// ignored.
+func SetSchemeCache(schemeCache string) ArcherOption {
+	return func(x *Archer) error {
+		if len(schemeCache) != 0 {
+			x.schemeCache = schemeCache
+		}
 		return nil
 	}
 }
 
 // SetBucket is an option setter for the NewArcher constructor
-// that sets the S3 bucket field of the Archer struct.
+// that sets the storage field of the Archer struct to an
+// S3 bucket.
 func SetBucket(name, region string) ArcherOption {
+	return SetStorage(fmt.Sprintf("s3://%s", name), bucket.SetRegion(region))
+}
+
+// SetStorage is an option setter for the NewArcher constructor
+// that sets the storage field of the Archer struct using a
+// storage URL (s3://bucket/prefix or file:///path/to/dir).
+// Any bucket options are used to configure an S3 backend.
+func SetStorage(storageURL string, opts ...bucket.Option) ArcherOption {
 	return func(x *Archer) error {
 
-		// create the bucket holder, check it and attach it
-		b, err := bucket.New(bucket.SetName(name), bucket.SetRegion(region))
+		// create the storage, check it and attach it
+		s, err := bucket.NewStorage(storageURL, opts...)
 		if err != nil {
 			return err
 		}
-		if err := b.Check(); err != nil {
+		if err := s.Check(); err != nil {
 			return err
 		}
-		x.bucket = b
+		x.storage = s
 		return nil
 	}
 }
 
 // SetManifest is an option setter for the NewArcher constructor
-// that downloads and opens a manifest and sets the appropriate
-// field of the Archer struct.
+// that sets where to collect the manifest from. This can be a
+// URL, a file:// URL or a path (relative to any schemes root).
+// The manifest is opened once all the options are set.
 func SetManifest(manifestURL string) ArcherOption {
 	return func(x *Archer) error {
+		if len(manifestURL) == 0 {
+			return errors.New("manifest URL is required")
+		}
+		x.manifestURL = manifestURL
+		return nil
+	}
+}
+
+// SetSketchParams is an option setter for the NewArcher
+// constructor that sets the default k-mer size, sketch size
+// and canonical mode used to sketch amplicons and reads.
+// These can be overridden by each process request.
+func SetSketchParams(kmerSize, sketchSize int, canonical bool) ArcherOption {
+	return func(x *Archer) error {
+		sketchParams := amplicons.SketchParams{
+			KmerSize:   kmerSize,
+			SketchSize: sketchSize,
+			Canonical:  canonical,
+		}
+		if err := sketchParams.Check(); err != nil {
+			return err
+		}
+		x.sketchParams = sketchParams
+		return nil
+	}
+}
+
+// SetLengthTolerance is an option setter for the NewArcher
+// constructor that sets the default fraction above/below the
+// mean amplicon size to keep reads within.
+func SetLengthTolerance(lengthTolerance float64) ArcherOption {
+	return func(x *Archer) error {
+		if lengthTolerance <= 0 || lengthTolerance >= 1 {
+			return errors.New("length tolerance must be greater than 0 and less than 1")
+		}
+		x.filters.lengthTolerance = float32(lengthTolerance)
+		return nil
+	}
+}
+
+// SetScoreThresholds is an option setter for the NewArcher
+// constructor that sets the default minimum scores for
+// matching a read to an amplicon, for Jaccard and containment
+// scoring.
+func SetScoreThresholds(jaccardThreshold, containmentThreshold float64) ArcherOption {
+	return func(x *Archer) error {
+		if jaccardThreshold <= 0 || jaccardThreshold > 1 || containmentThreshold <= 0 || containmentThreshold > 1 {
+			return errors.New("score thresholds must be greater than 0 and no more than 1")
+		}
+		x.filters.jaccardThreshold = float32(jaccardThreshold)
+		x.filters.containmentThreshold = float32(containmentThreshold)
+		return nil
+	}
+}
+
+// SetMinMeanQuality is an option setter for the NewArcher
+// constructor that sets the default minimum mean base
+// quality (Phred) for a read to be kept.
+func SetMinMeanQuality(minMeanQuality float64) ArcherOption {
+	return func(x *Archer) error {
+		if minMeanQuality < 0 || minMeanQuality > maxPhred {
+			return fmt.Errorf("minimum mean quality must be between 0 and %d", maxPhred)
+		}
+		x.filters.minMeanQuality = float32(minMeanQuality)
+		return nil
+	}
+}
+
+// SetQualityTrim is an option setter for the NewArcher
+// constructor that sets the default sliding window size
+// and minimum mean window quality (Phred) used to trim
+// the low quality end of reads. Reads aren't trimmed if
+// either is 0.
+func SetQualityTrim(window int, quality float64) ArcherOption {
+	return func(x *Archer) error {
+		if window < 0 {
+			return fmt.Errorf("trim window can't be negative")
+		}
+		if quality < 0 || quality > maxPhred {
+			return fmt.Errorf("trim quality must be between 0 and %d", maxPhred)
+		}
+		x.filters.trimWindow = int32(window)
+		x.filters.trimQuality = float32(quality)
+		return nil
+	}
+}
+
+// SetPrimerTrim is an option setter for the NewArcher
+// constructor that sets the default primer trimming for
+// kept reads (none, soft or hard).
+func SetPrimerTrim(mode string) ArcherOption {
+	return func(x *Archer) error {
+		m, err := parsePrimerTrim(mode)
+		if err != nil {
+			return err
+		}
+		x.filters.primerTrim = m
+		return nil
+	}
+}
+
+// SetMaxAmpliconDepth is an option setter for the NewArcher
+// constructor that sets the default maximum number of reads
+// to keep for each amplicon. The first reads assigned to an
+// amplicon are kept and 0 keeps all reads.
+func SetMaxAmpliconDepth(depth int) ArcherOption {
+	return func(x *Archer) error {
+		if depth < 0 {
+			return fmt.Errorf("max amplicon depth can't be negative")
+		}
+		x.filters.maxAmpliconDepth = int32(depth)
+		return nil
+	}
+}
+
+// SetHostIndex is an option setter for the NewArcher
+// constructor that loads a host k-mer index (built with
+// archer host). Reads assigned to an amplicon are then
+// removed if at least the threshold fraction of their
+// sampled k-mers are in the index. An empty path is
+// ignored.
+func SetHostIndex(path string, threshold float64) ArcherOption {
+	return func(x *Archer) error {
+		if len(path) == 0 {
+			return nil
+		}
+		if threshold <= 0 || threshold > 1 {
+			return fmt.Errorf("host threshold must be greater than 0 and no more than 1")
+		}
+		idx, err := host.Load(path)
+		if err != nil {
+			return fmt.Errorf("could not load host index: %w", err)
+		}
+		log.Infof("loaded host index with %d k-mers (k=%d, scaled=%d)", idx.GetNumHashes(), idx.GetKmerSize(), idx.GetScaled())
+		x.hostIndex = idx
+		x.hostThreshold = float32(threshold)
+		return nil
+	}
+}
+
+// SetMultiHits is an option setter for the NewArcher
+// constructor that sets the default multi-hit ratio and
+// action. A read is flagged as ambiguous or chimeric if
+// its second best amplicon score is at least the ratio
+// times its best score; the action sets which flagged
+// reads are discarded (keep, chimeric or all).
+func SetMultiHits(ratio float64, action string) ArcherOption {
+	return func(x *Archer) error {
+		if ratio <= 0 || ratio > 1 {
+			return fmt.Errorf("multi-hit ratio must be greater than 0 and no more than 1")
+		}
+		a, err := parseMultiHitAction(action)
+		if err != nil {
+			return err
+		}
+		x.filters.multiHitRatio = float32(ratio)
+		x.filters.multiHitAction = a
+		return nil
+	}
+}
+
+// SetSampleQC is an option setter for the NewArcher
+// constructor that sets the default number of kept
+// reads an amplicon needs to pass QC, and the estimated
+// reference coverage (percentage) below which a sample
+// is given a WARN or FAIL verdict.
+func SetSampleQC(minDepth int, warnCoverage, failCoverage float64) ArcherOption {
+	return func(x *Archer) error {
+		if minDepth < 1 {
+			return fmt.Errorf("QC minimum depth must be greater than 0")
+		}
+		if err := checkQCThresholds(warnCoverage, failCoverage); err != nil {
+			return err
+		}
+		x.filters.qcMinDepth = int32(minDepth)
+		x.filters.qcWarnCoverage = float32(warnCoverage)
+		x.filters.qcFailCoverage = float32(failCoverage)
+		return nil
+	}
+}
 
-		// download the manifest, unpack and attach it
-		manifest, err := amplicons.GetManifest(manifestURL)
+// SetQCGate is an option setter for the NewArcher
+// constructor that sets the QC gate, which is checked
+// after filtering and before upload. A sample fails if
+// it has fewer kept reads or amplicons at the QC depth
+// than the minimums, or if its off-target fraction is
+// above the maximum (a threshold of 0 isn't checked).
+// Failing samples are either not uploaded (block) or
+// are uploaded to the quarantine prefix (quarantine).
+// Reads are staged locally while the gate is checked.
+func SetQCGate(minKeptReads, minAmplicons int, maxOffTarget float64, action string) ArcherOption {
+	return func(x *Archer) error {
+		if minKeptReads < 0 || minAmplicons < 0 {
+			return fmt.Errorf("QC gate minimums can't be negative")
+		}
+		if maxOffTarget < 0 || maxOffTarget > 1 {
+			return fmt.Errorf("QC gate off-target fraction must be between 0 and 1")
+		}
+		quarantine, ok := qcGateActions[action]
+		if !ok {
+			return fmt.Errorf("unknown QC gate action: %v (must be block or quarantine)", action)
+		}
+		x.qcGate = qcGate{
+			minKeptReads: int32(minKeptReads),
+			minAmplicons: int32(minAmplicons),
+			maxOffTarget: float32(maxOffTarget),
+			quarantine:   quarantine,
+		}
+		return nil
+	}
+}
+
+// SetStagingDir is an option setter for the NewArcher
+// constructor that sets the directory used to stage
+// reads while the QC gate is checked. By default, reads
+// are staged alongside the database. An empty directory
+// is ignored.
+func SetStagingDir(dir string) ArcherOption {
+	return func(x *Archer) error {
+		if len(dir) != 0 {
+			x.stagingDir = dir
+		}
+		return nil
+	}
+}
+
+// SetHeaderPolicy is an option setter for the NewArcher
+// constructor that sets the default read header policy
+// for kept reads (keep, index or sample).
+func SetHeaderPolicy(policy string) ArcherOption {
+	return func(x *Archer) error {
+		p, err := parseHeaderPolicy(policy)
 		if err != nil {
 			return err
 		}
-		x.manifest = manifest
+		x.filters.headerPolicy = p
+		return nil
+	}
+}
+
+// SetHeaderMapDir is an option setter for the NewArcher
+// constructor that sets a local directory for files that
+// map scrubbed read headers back to the originals. These
+// files are never uploaded. An empty directory means no
+// mapping files are kept.
+func SetHeaderMapDir(dir string) ArcherOption {
+	return func(x *Archer) error {
+		x.headerMapDir = dir
+		return nil
+	}
+}
+
+// SetSchemesRoot is an option setter for the NewArcher
+// constructor that sets a local copy of the primer-schemes
+// repository. The manifest, primers and reference sequences
+// are then loaded from this directory instead of being
+// downloaded, allowing Archer to run offline. An empty
+// root is ignored.
+func SetSchemesRoot(schemesRoot string) ArcherOption {
+	return func(x *Archer) error {
+		if len(schemesRoot) == 0 {
+			return nil
+		}
+		info, err := os.Stat(schemesRoot)
+		if err != nil {
+			return fmt.Errorf("could not use primer schemes directory: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("primer schemes location is not a directory: %v", schemesRoot)
+		}
+		x.schemesRoot = schemesRoot
 		return nil
 	}
 }
@@ -138,11 +539,17 @@ func NewArcher(options ...ArcherOption) (api.ArcherServer, func() error, error)
 
 	// create the service
 	a := &Archer{
-		version:       apiVersion,
-		numWorkers:    2,
-		ampliconCache: make(map[string]*amplicons.AmpliconSet),
-		processChan:   make(chan *api.SampleInfo),
-		watcherChan:   nil,
+		version:          apiVersion,
+		numWorkers:       2,
+		maxRetries:       DefaultMaxRetries,
+		progressInterval: DefaultProgressInterval,
+		sketchParams:     amplicons.DefaultSketchParams(),
+		filters:          newFilterDefaults(),
+		ampliconCache:    make(map[string]*amplicons.AmpliconSet),
+		processChan:      make(chan *api.SampleInfo),
+		quit:             make(chan struct{}),
+		inFlight:         make(map[string]*inFlightSample),
+		watchers:         newWatcherHub(),
 	}
 
 	// set options
@@ -157,11 +564,25 @@ func NewArcher(options ...ArcherOption) (api.ArcherServer, func() error, error)
 		return nil, nil, errors.New("dbPath is required")
 	}
 
+	// collect the manifest, unpack and attach it
+	if len(a.manifestURL) != 0 {
+		manifest, err := amplicons.GetManifest(a.manifestURL, a.schemeOptions()...)
+		if err != nil {
+			return nil, nil, err
+		}
+		a.manifest = manifest
+	}
+
 	// start up the process request workers
 	for i := 0; i < a.numWorkers; i++ {
 		go a.processWorker()
 	}
 
+	// handle any samples left unfinished by the last service run
+	if err := a.recoverSamples(); err != nil {
+		return nil, nil, err
+	}
+
 	// return the instance and it's shutdown method
 	return a, a.shutdown, nil
 }
@@ -179,13 +600,15 @@ func (a *Archer) checkAPI(requestedAPI string) error {
 // shutdown will stop the Archer service gracefully.
 func (a *Archer) shutdown() error {
 
-	// shut down the job chan to stop the process workers
+	// stop queueing samples, then shut down the job chan to stop the process workers
+	a.Lock()
+	close(a.quit)
+	a.Unlock()
+	a.queueing.Wait()
 	close(a.processChan)
 
-	// shut down watcher channel if one exists
-	if a.watcherChan != nil {
-		close(a.watcherChan)
-	}
+	// disconnect any watchers
+	a.watchers.close()
 	// sync and close the db
 	if err := a.db.Sync(); err != nil {
 		return err
@@ -217,20 +640,89 @@ func (a *Archer) addSample(sample *api.SampleInfo) error {
 	return nil
 }
 
+// getSample will retrieve a sample from
+// the Archer db.
+func (a *Archer) getSample(sampleID string) (*api.SampleInfo, error) {
+
+	// lock the db for read access
+	a.RLock()
+	defer a.RUnlock()
+
+	// get the sample and unmarshal it
+	data, err := a.db.Get([]byte(sampleID))
+	if err != nil {
+		return nil, err
+	}
+	sample := &api.SampleInfo{}
+	if err := proto.Unmarshal(data, sample); err != nil {
+		return nil, err
+	}
+	return sample, nil
+}
+
+// getSampleIDs will return the IDs of all
+// the samples in the Archer db.
+//
+// The keys are collected before returning as
+// the db can't be written to whilst its keys
+// are being iterated over.
+func (a *Archer) getSampleIDs() []string {
+	var ids []string
+	for key := range a.db.Keys() {
+		ids = append(ids, string(key))
+	}
+	return ids
+}
+
+// finishSample will set the end time for a
+// sample, write it back to the db and let
+// any watchers know.
+func (a *Archer) finishSample(sample *api.SampleInfo, previousState api.State) error {
+	sample.EndTime = ptypes.TimestampNow()
+	if err := a.addSample(sample); err != nil {
+		return err
+	}
+	a.publishEvent(api.EventType_STATE_CHANGE, sample, previousState, nil)
+	return nil
+}
+
+// publishEvent will send a sample event to
+// any watchers.
+func (a *Archer) publishEvent(eventType api.EventType, sample *api.SampleInfo, previousState api.State, progress *api.SampleProgress) {
+	a.watchers.publish(&api.WatchResponse{
+		ApiVersion:    a.version,
+		Samples:       []*api.SampleInfo{sample},
+		EventType:     eventType,
+		PreviousState: previousState,
+		Progress:      progress,
+	})
+}
+
 // validateRequest will validate a service request.
 func (a *Archer) validateRequest(request *api.ProcessRequest) error {
 
-	// check input files exist
+	// check there is somewhere to upload to
+	if a.storage == nil {
+		return fmt.Errorf("no storage has been set up for the service")
+	}
+
+	// check input files exist and update the request
+	// with the files found for any directories or globs
 	if len(request.GetInputFASTQfiles()) == 0 {
 		return fmt.Errorf("no FASTQ files provided")
 	}
-	for _, f := range request.GetInputFASTQfiles() {
-		if _, err := os.Stat(f); err != nil {
-			if os.IsNotExist(err) {
-				return fmt.Errorf("%v does not exist", f)
-			}
-			return fmt.Errorf("can't access %v", f)
-		}
+	files, err := expandInputs(request.GetInputFASTQfiles())
+	if err != nil {
+		return err
+	}
+	request.InputFASTQfiles = files
+
+	// check the read filters
+	if err := checkFilters(request); err != nil {
+		return err
+	}
+	if err := a.checkQualityTrim(request); err != nil {
+		return err
 	}
 
 	// check requested scheme is in the ARTIC manifest
@@ -242,20 +734,89 @@ func (a *Archer) validateRequest(request *api.ProcessRequest) error {
 	}
 	request.Scheme = schemeTag
 
+	// check the sketch parameters
+	if request.GetKmerSize() < 0 || request.GetSketchSize() < 0 {
+		return fmt.Errorf("k-mer size and sketch size can't be negative")
+	}
+	if _, ok := api.KmerStrand_name[int32(request.GetKmerStrand())]; !ok {
+		return fmt.Errorf("unsupported k-mer strand: %v", request.GetKmerStrand())
+	}
+	sketchParams := a.getSketchParams(request)
+	if err := sketchParams.Check(); err != nil {
+		return err
+	}
+
 	// check that the current session has the requested amplicon set stored, or download it now
-	if _, ok := a.ampliconCache[generateAmpliconSetID(request.GetScheme(), request.GetSchemeVersion())]; !ok {
-		ampliconSet, err := amplicons.NewAmpliconSet(a.manifest, request.GetScheme(), request.GetSchemeVersion())
-		if err != nil {
-			return err
-		}
-		a.ampliconCache[generateAmpliconSetID(request.GetScheme(), request.GetSchemeVersion())] = ampliconSet
+	as, err := a.getAmpliconSet(request.GetScheme(), request.GetSchemeVersion(), sketchParams)
+	if err != nil {
+		return err
+	}
+
+	// check the read length bounds work for this scheme
+	lengthMin, lengthMax := a.getLengthBounds(request, int32(as.GetMeanSize()))
+	if lengthMin > lengthMax {
+		return fmt.Errorf("minimum read length is greater than the maximum for this scheme (%d > %d)", lengthMin, lengthMax)
 	}
 
+	// reads shorter than the k-mer size can't be sketched
+	if int(lengthMin) < sketchParams.KmerSize {
+		return fmt.Errorf("minimum read length is less than the k-mer size (%d < %d)", lengthMin, sketchParams.KmerSize)
+	}
 	return nil
 }
 
+// getSketchParams returns the sketch parameters for
+// a request, using the server settings for any that
+// the request does not set.
+func (a *Archer) getSketchParams(request *api.ProcessRequest) amplicons.SketchParams {
+	sketchParams := a.sketchParams
+	if request.GetKmerSize() != 0 {
+		sketchParams.KmerSize = int(request.GetKmerSize())
+	}
+	if request.GetSketchSize() != 0 {
+		sketchParams.SketchSize = int(request.GetSketchSize())
+	}
+	switch request.GetKmerStrand() {
+	case api.KmerStrand_CANONICAL:
+		sketchParams.Canonical = true
+	case api.KmerStrand_FORWARD:
+		sketchParams.Canonical = false
+	}
+	return sketchParams
+}
+
+// getAmpliconSet will return the amplicon set for a scheme
+// and sketch parameters from the cache, downloading and
+// sketching it first if needed.
+func (a *Archer) getAmpliconSet(scheme string, version int32, sketchParams amplicons.SketchParams) (*amplicons.AmpliconSet, error) {
+	a.cacheLock.Lock()
+	defer a.cacheLock.Unlock()
+	ampliconSetID := generateAmpliconSetID(scheme, version, sketchParams)
+	if ampliconSet, ok := a.ampliconCache[ampliconSetID]; ok {
+		return ampliconSet, nil
+	}
+	if a.manifest == nil {
+		return nil, errors.New("no primer scheme manifest has been loaded")
+	}
+	ampliconSet, err := amplicons.NewAmpliconSet(a.manifest, scheme, version, append(a.schemeOptions(), amplicons.SetSketchParams(sketchParams))...)
+	if err != nil {
+		return nil, err
+	}
+	a.ampliconCache[ampliconSetID] = ampliconSet
+	return ampliconSet, nil
+}
+
+// schemeOptions returns the options used to
+// collect the manifest and primer schemes.
+func (a *Archer) schemeOptions() []amplicons.Option {
+	return []amplicons.Option{
+		amplicons.SetLocalRoot(a.schemesRoot),
+		amplicons.SetCacheDir(a.schemeCache),
+	}
+}
+
 // generateAmpliconSetID is a helper function
 // to generate a string id.
-func generateAmpliconSetID(scheme string, version int32) string {
-	return fmt.Sprintf("%v-%v", scheme, version)
+func generateAmpliconSetID(scheme string, version int32, sketchParams amplicons.SketchParams) string {
+	return fmt.Sprintf("%v-%v-%v", scheme, version, sketchParams)
 }
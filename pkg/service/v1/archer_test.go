@@ -1,15 +1,97 @@
 package service
 
 import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/will-rowe/archer/pkg/amplicons"
+	api "github.com/will-rowe/archer/pkg/api/v1"
 )
 
 var (
 	dbLocation  string = "./tmp"
-	manifestURL string = "https://raw.githubusercontent.com/artic-network/primer-schemes/master/schemes_manifest.json"
+	testSchemes string = "../../amplicons/testdata/primer-schemes"
 )
 
+// testRead is a read to write to a test FASTQ file.
+type testRead struct {
+	seq  string
+	qual byte
+}
+
+// newTestArcher returns an Archer that uses the local
+// test primer scheme and uploads to a directory in
+// the db path.
+func newTestArcher(t *testing.T, dbPath string, options ...ArcherOption) (*Archer, func() error) {
+	uploads, err := filepath.Abs(filepath.Join(dbPath, "uploads"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	options = append([]ArcherOption{SetDb(dbPath), SetStorage("file://" + uploads), SetSchemesRoot(testSchemes), SetManifest("schemes_manifest.json")}, options...)
+	aInterface, shutdown, err := NewArcher(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aInterface.(*Archer), shutdown
+}
+
+// getTestReference returns the reference sequence for
+// the local test primer scheme.
+func getTestReference(t *testing.T) string {
+	fh, err := os.Open(filepath.Join(testSchemes, "test/V1/test.reference.fasta"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	var seq strings.Builder
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		if !strings.HasPrefix(scanner.Text(), ">") {
+			seq.WriteString(scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return seq.String()
+}
+
+// writeTestFASTQ will write reads to a FASTQ file.
+func writeTestFASTQ(t *testing.T, path string, reads []testRead) {
+	var fastq strings.Builder
+	for i, read := range reads {
+		fmt.Fprintf(&fastq, "@read%d\n%s\n+\n%s\n", i, read.seq, strings.Repeat(string(read.qual), len(read.seq)))
+	}
+	if err := ioutil.WriteFile(path, []byte(fastq.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitForSample will wait for a sample to finish
+// processing and return it.
+func waitForSample(t *testing.T, a *Archer, id string) *api.SampleInfo {
+	for i := 0; i < 100; i++ {
+		sample, err := a.getSample(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch sample.GetState() {
+		case api.State_UNKNOWN, api.State_RUNNING:
+			time.Sleep(50 * time.Millisecond)
+		default:
+			return sample
+		}
+	}
+	t.Fatalf("sample did not finish processing: %v", id)
+	return nil
+}
+
 // cleanUp is called to remove the database
 // after testing completes
 func cleanUp() error {
@@ -21,11 +103,12 @@ func cleanUp() error {
 func TestAPIversion(t *testing.T) {
 	v1 := "1"
 	v2 := "2"
+	defer cleanUp()
 	aInterface, shutdown, err := NewArcher(SetDb(dbLocation))
-	var a *Archer = aInterface.(*Archer)
 	if err != nil {
 		t.Fatal(err)
 	}
+	a := aInterface.(*Archer)
 	if err := a.checkAPI(v1); err != nil {
 		t.Fatal(err)
 	}
@@ -37,23 +120,133 @@ func TestAPIversion(t *testing.T) {
 	}
 }
 
-// TestPrimerScheme will make sure the manifest and individual
-// primer schemes can be downloaded.
+// TestPrimerScheme will make sure the manifest and each
+// primer scheme in it can be loaded (using the bundled
+// test schemes so that no downloads are needed).
 func TestPrimerScheme(t *testing.T) {
-	aInterface, shutdown, err := NewArcher(SetDb(dbLocation), SetManifest(manifestURL))
-	var a *Archer = aInterface.(*Archer)
+	defer cleanUp()
+	aInterface, shutdown, err := NewArcher(SetDb(dbLocation), SetSchemesRoot(testSchemes), SetManifest("schemes_manifest.json"))
 	if err != nil {
 		t.Fatal(err)
 	}
+	a := aInterface.(*Archer)
 
-	// TODO: check each scheme in the manifest can be download
-	_ = a
-
-	// final test so remove the tmp db
+	// check each scheme in the manifest can be loaded
+	if len(a.manifest.GetSchemes()) == 0 {
+		t.Fatal("no schemes in the manifest")
+	}
+	for scheme, metadata := range a.manifest.GetSchemes() {
+		if _, err := a.getAmpliconSet(scheme, metadata.GetLatestVersion(), a.sketchParams); err != nil {
+			t.Fatalf("could not load scheme %v: %v", scheme, err)
+		}
+	}
 	if err := shutdown(); err != nil {
 		t.Fatal(err)
 	}
-	if err := cleanUp(); err != nil {
+}
+
+// TestLocalSchemes will make sure the manifest and primer
+// schemes can be loaded from a local primer-schemes root.
+func TestLocalSchemes(t *testing.T) {
+	schemesDb := "./tmp-schemes"
+	defer os.RemoveAll(schemesDb)
+	a, shutdown := newTestArcher(t, schemesDb)
+	defer shutdown()
+	ampliconSet, err := a.getAmpliconSet("test", 1, a.sketchParams)
+	if err != nil {
 		t.Fatal(err)
 	}
+	if ampliconSet.GetNumAmplicons() != 3 {
+		t.Fatalf("incorrect number of amplicons loaded: wanted 3, got %d", ampliconSet.GetNumAmplicons())
+	}
+
+	// different sketch params should be cached separately
+	sketchParams := a.getSketchParams(&api.ProcessRequest{KmerSize: 11, KmerStrand: api.KmerStrand_FORWARD})
+	if sketchParams != (amplicons.SketchParams{KmerSize: 11, SketchSize: amplicons.DefaultSketchSize, Canonical: false}) {
+		t.Fatalf("request did not override the sketch params: %v", sketchParams)
+	}
+	otherSet, err := a.getAmpliconSet("test", 1, sketchParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherSet == ampliconSet || otherSet.GetSketchParams() != sketchParams {
+		t.Fatal("amplicon sets with different sketch params were not cached separately")
+	}
+	if len(a.ampliconCache) != 2 {
+		t.Fatalf("wanted 2 cached amplicon sets, got %d", len(a.ampliconCache))
+	}
+}
+
+// TestValidateRequest will check that the read scoring
+// options in a process request are checked.
+func TestValidateRequest(t *testing.T) {
+	validateDb := "./tmp-validate"
+	defer os.RemoveAll(validateDb)
+	a, shutdown := newTestArcher(t, validateDb)
+	defer shutdown()
+	fastq := filepath.Join(validateDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{"ACGT", 'I'}})
+	tests := []struct {
+		scoring    api.Scoring
+		threshold  float32
+		kmerSize   int32
+		kmerStrand api.KmerStrand
+		valid      bool
+		used       float32
+	}{
+		{api.Scoring_JACCARD, 0, 0, api.KmerStrand_DEFAULT_STRAND, true, DefaultJaccardThreshold},
+		{api.Scoring_CONTAINMENT, 0, 0, api.KmerStrand_DEFAULT_STRAND, true, DefaultContainmentThreshold},
+		{api.Scoring_CONTAINMENT, 0.9, 15, api.KmerStrand_FORWARD, true, 0.9},
+		{api.Scoring_JACCARD, 1.1, 0, api.KmerStrand_DEFAULT_STRAND, false, 0},
+		{api.Scoring_JACCARD, -0.1, 0, api.KmerStrand_DEFAULT_STRAND, false, 0},
+		{api.Scoring(7), 0, 0, api.KmerStrand_DEFAULT_STRAND, false, 0},
+		{api.Scoring_JACCARD, 0, -1, api.KmerStrand_DEFAULT_STRAND, false, 0},
+		{api.Scoring_JACCARD, 0, amplicons.MaxKmerSize + 1, api.KmerStrand_DEFAULT_STRAND, false, 0},
+		{api.Scoring_JACCARD, 0, 0, api.KmerStrand(5), false, 0},
+	}
+	for i, test := range tests {
+		request := &api.ProcessRequest{
+			ApiVersion:      "1",
+			SampleID:        "sample",
+			InputFASTQfiles: []string{fastq},
+			Scheme:          "test-scheme",
+			SchemeVersion:   1,
+			Scoring:         test.scoring,
+			ScoreThreshold:  test.threshold,
+			KmerSize:        test.kmerSize,
+			KmerStrand:      test.kmerStrand,
+		}
+		err := a.validateRequest(request)
+		if (err == nil) != test.valid {
+			t.Fatalf("test %d: wanted valid == %v, got error: %v", i, test.valid, err)
+		}
+		if test.valid && a.getScoreThreshold(request) != test.used {
+			t.Fatalf("test %d: wanted score threshold %f, got %f", i, test.used, a.getScoreThreshold(request))
+		}
+	}
+	// the minimum read length can't be below the k-mer size, including any requested k-mer size
+	lengthTests := []struct {
+		minLength int32
+		kmerSize  int32
+		valid     bool
+	}{
+		{int32(amplicons.DefaultKmerSize), 0, true},
+		{int32(amplicons.DefaultKmerSize) - 1, 0, false},
+		{10, 7, true},
+		{10, 11, false},
+	}
+	for i, test := range lengthTests {
+		request := &api.ProcessRequest{
+			ApiVersion:      "1",
+			SampleID:        "sample",
+			InputFASTQfiles: []string{fastq},
+			Scheme:          "test-scheme",
+			SchemeVersion:   1,
+			MinLength:       test.minLength,
+			KmerSize:        test.kmerSize,
+		}
+		if err := a.validateRequest(request); (err == nil) != test.valid {
+			t.Fatalf("length test %d: wanted valid == %v, got error: %v", i, test.valid, err)
+		}
+	}
 }
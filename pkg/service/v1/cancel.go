@@ -2,12 +2,66 @@ package service
 
 import (
 	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	api "github.com/will-rowe/archer/pkg/api/v1"
 )
 
-// Cancel is used to...
+// Cancel will stop processing for a queued or running sample.
+//
+// A queued sample is marked as cancelled straight away. A
+// running sample has its processing stopped and its upload
+// aborted, after which the process worker marks it as
+// cancelled.
 func (a *Archer) Cancel(ctx context.Context, request *api.CancelRequest) (*api.CancelResponse, error) {
+	log.Infof("cancel request received for %v", request.GetId())
+
+	// check we have received a supported API request
+	if err := a.checkAPI(request.GetApiVersion()); err != nil {
+		return nil, err
+	}
+	if len(request.GetId()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no sample ID provided")
+	}
+
+	// check the sample is queued or running
+	a.Lock()
+	job, ok := a.inFlight[request.GetId()]
+	if !ok {
+		a.Unlock()
+		if !a.db.Has([]byte(request.GetId())) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				fmt.Sprintf("sample not found in the database (%s)", request.GetId()),
+			)
+		}
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			fmt.Sprintf("sample has already finished processing (%s)", request.GetId()),
+		)
+	}
+
+	// stop the processing
+	job.cancel()
+
+	// a running sample will be finished by the process worker
+	if job.running {
+		a.Unlock()
+		log.Infof("cancelled running sample %v", request.GetId())
+		return &api.CancelResponse{}, nil
+	}
 
-	return nil, nil
+	// a queued sample can be finished now
+	job.sample.State = api.State_CANCELLED
+	delete(a.inFlight, request.GetId())
+	a.Unlock()
+	if err := a.finishSample(job.sample, api.State_UNKNOWN); err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("could not update sample: %v", err))
+	}
+	log.Infof("cancelled queued sample %v", request.GetId())
+	return &api.CancelResponse{}, nil
 }
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+	"github.com/will-rowe/archer/pkg/bucket"
+)
+
+// blockingStorage wraps a Storage so that the first
+// upload blocks until its context is cancelled.
+type blockingStorage struct {
+	bucket.Storage
+	started chan struct{}
+	blocked int32
+}
+
+// Upload implements the Storage interface.
+func (b *blockingStorage) Upload(ctx context.Context, reader io.Reader, key string) (string, error) {
+	if atomic.CompareAndSwapInt32(&b.blocked, 0, 1) {
+		close(b.started)
+		<-ctx.Done()
+	}
+	return b.Storage.Upload(ctx, reader, key)
+}
+
+// TestArcher_Cancel will check that queued samples can
+// be cancelled and that unknown or finished samples
+// return the appropriate error codes.
+func TestArcher_Cancel(t *testing.T) {
+	cancelDb := "./tmp-cancel"
+	defer os.RemoveAll(cancelDb)
+	aInterface, shutdown, err := NewArcher(SetDb(cancelDb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := aInterface.(*Archer)
+
+	// unknown sample
+	_, err = a.Cancel(context.Background(), &api.CancelRequest{ApiVersion: apiVersion, Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for unknown sample, got %v", err)
+	}
+
+	// queued sample
+	sample, err := NewSample(SetID("queued"), SetRequest(&api.ProcessRequest{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.addSample(sample); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.inFlight[sample.GetSampleID()] = &inFlightSample{sample: sample, ctx: ctx, cancel: cancel}
+	if _, err := a.Cancel(context.Background(), &api.CancelRequest{ApiVersion: apiVersion, Id: "queued"}); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("sample context was not cancelled")
+	}
+	stored, err := a.getSample("queued")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.GetState() != api.State_CANCELLED || stored.GetEndTime() == nil {
+		t.Fatalf("cancelled sample not updated in db: got state %v", stored.GetState())
+	}
+
+	// finished sample
+	_, err = a.Cancel(context.Background(), &api.CancelRequest{ApiVersion: apiVersion, Id: "queued"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for finished sample, got %v", err)
+	}
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCancelRunning will check that cancelling a running
+// sample aborts its upload and frees up the worker.
+func TestCancelRunning(t *testing.T) {
+	cancelDb := "./tmp-cancel-running"
+	defer os.RemoveAll(cancelDb)
+	a, shutdown := newTestArcher(t, cancelDb, SetNumWorkers(1))
+	defer shutdown()
+	storage := &blockingStorage{Storage: a.storage, started: make(chan struct{})}
+	a.storage = storage
+
+	// start a sample and wait for its upload to begin
+	ref := getTestReference(t)
+	fastq := filepath.Join(cancelDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[30:424], 'I'}, {ref[350:774], 'I'}})
+	request := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "running",
+		InputFASTQfiles: []string{fastq},
+		Scheme:          "test",
+		SchemeVersion:   1,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-storage.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("upload did not start")
+	}
+
+	// cancel it and check nothing was uploaded
+	if _, err := a.Cancel(context.Background(), &api.CancelRequest{ApiVersion: apiVersion, Id: "running"}); err != nil {
+		t.Fatal(err)
+	}
+	sample := waitForSample(t, a, "running")
+	if sample.GetState() != api.State_CANCELLED || len(sample.GetErrors()) != 0 || len(sample.GetEndpoint()) != 0 || sample.GetEndTime() == nil {
+		t.Fatalf("running sample was not cancelled: %v (%v)", sample.GetState(), sample.GetErrors())
+	}
+	if uploads, err := ioutil.ReadDir(filepath.Join(cancelDb, "uploads")); err != nil || len(uploads) != 0 {
+		t.Fatalf("cancelled sample left an upload behind: %v", err)
+	}
+	if _, err := a.Cancel(context.Background(), &api.CancelRequest{ApiVersion: apiVersion, Id: "running"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for cancelled sample, got %v", err)
+	}
+
+	// the worker should be free to process the next sample
+	request.SampleID = "next"
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	if sample := waitForSample(t, a, "next"); sample.GetState() != api.State_SUCCESS {
+		t.Fatalf("worker did not process the next sample: %v (%v)", sample.GetState(), sample.GetErrors())
+	}
+}
+
+// TestCancelWhileQueueing will check that a sample can be
+// cancelled whilst its queued event is being published.
+func TestCancelWhileQueueing(t *testing.T) {
+	cancelDb := "./tmp-cancel-queueing"
+	defer os.RemoveAll(cancelDb)
+	a, shutdown := newTestArcher(t, cancelDb, SetNumWorkers(1))
+	defer shutdown()
+	storage := &blockingStorage{Storage: a.storage, started: make(chan struct{})}
+	a.storage = storage
+	sub := a.watchers.subscribe(watcherBufferSize)
+	defer a.watchers.unsubscribe(sub)
+
+	// keep the worker busy so that the next sample stays queued
+	ref := getTestReference(t)
+	fastq := filepath.Join(cancelDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[30:424], 'I'}})
+	request := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "running",
+		InputFASTQfiles: []string{fastq},
+		Scheme:          "test",
+		SchemeVersion:   1,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	<-storage.started
+
+	// queue a sample and cancel it as soon as it is registered
+	queued := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "queued",
+		InputFASTQfiles: []string{fastq},
+		Scheme:          "test",
+		SchemeVersion:   1,
+	}
+	go a.Process(context.Background(), queued)
+	for {
+		a.RLock()
+		_, ok := a.inFlight["queued"]
+		a.RUnlock()
+		if ok {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := a.Cancel(context.Background(), &api.CancelRequest{ApiVersion: apiVersion, Id: "queued"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the queued event should show the sample before it was cancelled
+	for event := range sub.updates {
+		if event.GetEventType() == api.EventType_QUEUED && event.GetSamples()[0].GetSampleID() == "queued" {
+			if event.GetSamples()[0].GetState() != api.State_UNKNOWN {
+				t.Fatalf("queued event has the wrong state: %v", event.GetSamples()[0].GetState())
+			}
+			break
+		}
+	}
+	if _, err := a.Cancel(context.Background(), &api.CancelRequest{ApiVersion: apiVersion, Id: "running"}); err != nil {
+		t.Fatal(err)
+	}
+	if sample := waitForSample(t, a, "queued"); sample.GetState() != api.State_CANCELLED {
+		t.Fatalf("queued sample was not cancelled: %v", sample.GetState())
+	}
+	waitForSample(t, a, "running")
+}
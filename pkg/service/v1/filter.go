@@ -0,0 +1,330 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+const (
+
+	// DefaultLengthTolerance is the fraction above/below the mean amplicon size to set max/min length read filtering to
+	DefaultLengthTolerance = 0.2
+
+	// DefaultJaccardThreshold is the minimum jaccard distance to match a read to an amplicon
+	DefaultJaccardThreshold = 0.7
+
+	// DefaultContainmentThreshold is the minimum containment score to match a read to an amplicon
+	DefaultContainmentThreshold = 0.7
+
+	// DefaultMinMeanQuality is the minimum mean base quality for a read (0 keeps all reads)
+	DefaultMinMeanQuality = 0.0
+
+	// DefaultMultiHitRatio is the fraction of the best amplicon score a second amplicon must reach to flag a read as ambiguous or chimeric
+	DefaultMultiHitRatio = 0.6
+
+	// maxPhred is the largest Phred score that can be encoded in a FASTQ (Phred+33)
+	maxPhred = 93
+
+	// qualityBins is the number of bins in the quality histograms (the last bin holds all higher scores)
+	qualityBins = 61
+)
+
+// phredErrors is a lookup of the error probability
+// for each Phred score.
+var phredErrors [maxPhred + 1]float64
+
+func init() {
+	for q := range phredErrors {
+		phredErrors[q] = math.Pow(10, -float64(q)/10)
+	}
+}
+
+// filterDefaults are the server settings used for
+// any read filters that a process request doesn't
+// set.
+type filterDefaults struct {
+	lengthTolerance      float32
+	jaccardThreshold     float32
+	containmentThreshold float32
+	minMeanQuality       float32
+	trimWindow           int32
+	trimQuality          float32
+	primerTrim           api.PrimerTrim
+	maxAmpliconDepth     int32
+	headerPolicy         api.HeaderPolicy
+	multiHitRatio        float32
+	multiHitAction       api.MultiHitAction
+	qcMinDepth           int32
+	qcWarnCoverage       float32
+	qcFailCoverage       float32
+}
+
+// newFilterDefaults returns the default read filters.
+func newFilterDefaults() filterDefaults {
+	return filterDefaults{
+		lengthTolerance:      DefaultLengthTolerance,
+		jaccardThreshold:     DefaultJaccardThreshold,
+		containmentThreshold: DefaultContainmentThreshold,
+		minMeanQuality:       DefaultMinMeanQuality,
+		primerTrim:           api.PrimerTrim_NO_TRIM,
+		headerPolicy:         api.HeaderPolicy_KEEP_HEADERS,
+		multiHitRatio:        DefaultMultiHitRatio,
+		multiHitAction:       api.MultiHitAction_KEEP_MULTI_HIT,
+		qcMinDepth:           DefaultQCMinDepth,
+		qcWarnCoverage:       DefaultQCWarnCoverage,
+		qcFailCoverage:       DefaultQCFailCoverage,
+	}
+}
+
+// checkFilters will check the read filters
+// requested for a sample.
+func checkFilters(request *api.ProcessRequest) error {
+	if _, ok := api.Scoring_name[int32(request.GetScoring())]; !ok {
+		return fmt.Errorf("unsupported scoring: %v", request.GetScoring())
+	}
+	if request.GetScoreThreshold() < 0 || request.GetScoreThreshold() > 1 {
+		return fmt.Errorf("score threshold must be between 0 and 1")
+	}
+	if request.GetMinLength() < 0 || request.GetMaxLength() < 0 {
+		return fmt.Errorf("read length bounds can't be negative")
+	}
+	if request.GetMaxLength() != 0 && request.GetMinLength() > request.GetMaxLength() {
+		return fmt.Errorf("minimum read length is greater than the maximum (%d > %d)", request.GetMinLength(), request.GetMaxLength())
+	}
+	if request.GetLengthTolerance() < 0 || request.GetLengthTolerance() >= 1 {
+		return fmt.Errorf("length tolerance must be at least 0 and less than 1")
+	}
+	if request.GetMinMeanQuality() < 0 || request.GetMinMeanQuality() > maxPhred {
+		return fmt.Errorf("minimum mean quality must be between 0 and %d", maxPhred)
+	}
+	if request.GetTrimWindow() < 0 {
+		return fmt.Errorf("trim window can't be negative")
+	}
+	if request.GetTrimQuality() < 0 || request.GetTrimQuality() > maxPhred {
+		return fmt.Errorf("trim quality must be between 0 and %d", maxPhred)
+	}
+	if _, ok := api.PrimerTrim_name[int32(request.GetPrimerTrim())]; !ok {
+		return fmt.Errorf("unsupported primer trim: %v", request.GetPrimerTrim())
+	}
+	if request.GetMaxAmpliconDepth() < 0 {
+		return fmt.Errorf("max amplicon depth can't be negative")
+	}
+	if _, ok := api.HeaderPolicy_name[int32(request.GetHeaderPolicy())]; !ok {
+		return fmt.Errorf("unsupported header policy: %v", request.GetHeaderPolicy())
+	}
+	if request.GetMultiHitRatio() < 0 || request.GetMultiHitRatio() > 1 {
+		return fmt.Errorf("multi-hit ratio must be between 0 and 1")
+	}
+	if _, ok := api.MultiHitAction_name[int32(request.GetMultiHitAction())]; !ok {
+		return fmt.Errorf("unsupported multi-hit action: %v", request.GetMultiHitAction())
+	}
+	if request.GetQcMinDepth() < 0 {
+		return fmt.Errorf("QC minimum depth can't be negative")
+	}
+	return nil
+}
+
+// getLengthBounds returns the minimum and maximum read
+// lengths for a request. Any bounds not set by the
+// request are set relative to the mean amplicon size.
+func (a *Archer) getLengthBounds(request *api.ProcessRequest, meanAmpliconSize int32) (int32, int32) {
+	tolerance := a.filters.lengthTolerance
+	if request.GetLengthTolerance() != 0 {
+		tolerance = request.GetLengthTolerance()
+	}
+	lengthRange := int32(float64(tolerance) * float64(meanAmpliconSize))
+	lengthMin, lengthMax := meanAmpliconSize-lengthRange, meanAmpliconSize+lengthRange
+	if request.GetMinLength() != 0 {
+		lengthMin = request.GetMinLength()
+	}
+	if request.GetMaxLength() != 0 {
+		lengthMax = request.GetMaxLength()
+	}
+	return lengthMin, lengthMax
+}
+
+// getScoreThreshold returns the score threshold for
+// a request, using the server setting for the requested
+// scoring if the request does not set one.
+func (a *Archer) getScoreThreshold(request *api.ProcessRequest) float32 {
+	if request.GetScoreThreshold() != 0 {
+		return request.GetScoreThreshold()
+	}
+	if request.GetScoring() == api.Scoring_CONTAINMENT {
+		return a.filters.containmentThreshold
+	}
+	return a.filters.jaccardThreshold
+}
+
+// getMinMeanQuality returns the minimum mean base
+// quality for a request, using the server setting if
+// the request does not set one.
+func (a *Archer) getMinMeanQuality(request *api.ProcessRequest) float32 {
+	if request.GetMinMeanQuality() != 0 {
+		return request.GetMinMeanQuality()
+	}
+	return a.filters.minMeanQuality
+}
+
+// getQualityTrim returns the window size and quality for
+// sliding-window quality trimming, using the server
+// settings for any that the request does not set. Both
+// are 0 if reads shouldn't be trimmed.
+func (a *Archer) getQualityTrim(request *api.ProcessRequest) (int32, float32) {
+	window, quality := a.filters.trimWindow, a.filters.trimQuality
+	if request.GetTrimWindow() != 0 {
+		window = request.GetTrimWindow()
+	}
+	if request.GetTrimQuality() != 0 {
+		quality = request.GetTrimQuality()
+	}
+	if window == 0 || quality == 0 {
+		return 0, 0
+	}
+	return window, quality
+}
+
+// checkQualityTrim will check that a request setting
+// only one of the trim window and trim quality can be
+// met by the server settings, rather than silently
+// turning off trimming.
+func (a *Archer) checkQualityTrim(request *api.ProcessRequest) error {
+	if request.GetTrimWindow() != 0 && request.GetTrimQuality() == 0 && a.filters.trimQuality == 0 {
+		return fmt.Errorf("trim window was set without a trim quality, and the server has no default trim quality")
+	}
+	if request.GetTrimQuality() != 0 && request.GetTrimWindow() == 0 && a.filters.trimWindow == 0 {
+		return fmt.Errorf("trim quality was set without a trim window, and the server has no default trim window")
+	}
+	return nil
+}
+
+// getMaxAmpliconDepth returns the maximum number of
+// reads to keep for each amplicon, using the server
+// setting if the request does not set one. It is 0
+// if there is no cap.
+func (a *Archer) getMaxAmpliconDepth(request *api.ProcessRequest) int32 {
+	if request.GetMaxAmpliconDepth() != 0 {
+		return request.GetMaxAmpliconDepth()
+	}
+	return a.filters.maxAmpliconDepth
+}
+
+// setFilters will record the read filters used for a
+// sample in its stats. The mean amplicon size must
+// already be set.
+func (a *Archer) setFilters(sample *api.SampleInfo) {
+	request := sample.GetProcessRequest()
+	stats := sample.GetProcessStats()
+	stats.LengthMin, stats.LengthMax = a.getLengthBounds(request, stats.GetMeanAmpliconSize())
+	stats.Scoring = request.GetScoring()
+	stats.ScoreThreshold = a.getScoreThreshold(request)
+	stats.MinMeanQuality = a.getMinMeanQuality(request)
+	stats.TrimWindow, stats.TrimQuality = a.getQualityTrim(request)
+	stats.PrimerTrim = a.getPrimerTrim(request)
+	stats.MaxAmpliconDepth = a.getMaxAmpliconDepth(request)
+	stats.HeaderPolicy = a.getHeaderPolicy(request)
+	stats.MultiHitRatio, stats.MultiHitAction = a.getMultiHit(request)
+	if a.hostIndex != nil {
+		stats.HostThreshold = a.hostThreshold
+	}
+	resetFilterStats(stats)
+}
+
+// resetFilterStats will zero the read filter
+// counts for a sample, allocating them if needed.
+func resetFilterStats(stats *api.SampleStats) {
+	atomic.StoreInt32(&stats.TrimmedReads, 0)
+	atomic.StoreInt32(&stats.AmbiguousReads, 0)
+	atomic.StoreInt32(&stats.ChimericReads, 0)
+	if stats.DroppedReads == nil {
+		stats.DroppedReads = &api.DroppedReads{}
+	}
+	atomic.StoreInt32(&stats.DroppedReads.Length, 0)
+	atomic.StoreInt32(&stats.DroppedReads.Quality, 0)
+	atomic.StoreInt32(&stats.DroppedReads.NoHit, 0)
+	atomic.StoreInt32(&stats.DroppedReads.DepthCap, 0)
+	atomic.StoreInt32(&stats.DroppedReads.Host, 0)
+	atomic.StoreInt32(&stats.DroppedReads.MultiHit, 0)
+	if stats.PrimerTrimStats == nil {
+		stats.PrimerTrimStats = &api.PrimerTrimStats{}
+	}
+	atomic.StoreInt32(&stats.PrimerTrimStats.TrimmedReads, 0)
+	atomic.StoreInt32(&stats.PrimerTrimStats.StartPrimers, 0)
+	atomic.StoreInt32(&stats.PrimerTrimStats.EndPrimers, 0)
+	atomic.StoreInt64(&stats.PrimerTrimStats.TrimmedBases, 0)
+	if len(stats.QualityHistogram) != qualityBins {
+		stats.QualityHistogram = make([]int32, qualityBins)
+	}
+	if len(stats.KeptQualityHistogram) != qualityBins {
+		stats.KeptQualityHistogram = make([]int32, qualityBins)
+	}
+	for i := 0; i < qualityBins; i++ {
+		atomic.StoreInt32(&stats.QualityHistogram[i], 0)
+		atomic.StoreInt32(&stats.KeptQualityHistogram[i], 0)
+	}
+}
+
+// getQualityBin returns the quality histogram bin
+// for a mean base quality, which is the quality
+// rounded to the nearest Phred score.
+func getQualityBin(meanQuality float64) int {
+	bin := int(math.Round(meanQuality))
+	if bin >= qualityBins {
+		return qualityBins - 1
+	}
+	if bin < 0 {
+		return 0
+	}
+	return bin
+}
+
+// qualityTrim returns the length to trim a read to using
+// a sliding window from the start of the read. The read
+// is cut at the first window where the mean Phred score
+// (Phred+33) drops below the minimum. A read shorter than
+// the window is checked as a single window.
+func qualityTrim(qual string, window int, minQuality float64) int {
+	if window > len(qual) {
+		window = len(qual)
+	}
+	if window == 0 {
+		return len(qual)
+	}
+	minSum := minQuality * float64(window)
+	sum := 0
+	for i := 0; i < len(qual); i++ {
+		sum += int(qual[i]) - 33
+		if i >= window {
+			sum -= int(qual[i-window]) - 33
+		}
+		if i >= window-1 && float64(sum) < minSum {
+			return i - window + 1
+		}
+	}
+	return len(qual)
+}
+
+// getMeanQuality returns the mean base quality of a read
+// in Phred+33 encoding. The mean is taken over the base
+// error probabilities, rather than the Phred scores, so
+// that a few low quality bases aren't hidden.
+func getMeanQuality(qual string) float64 {
+	if len(qual) == 0 {
+		return 0.0
+	}
+	sum := 0.0
+	for i := 0; i < len(qual); i++ {
+		score := int(qual[i]) - 33
+		if score < 0 {
+			score = 0
+		}
+		if score > maxPhred {
+			score = maxPhred
+		}
+		sum += phredErrors[score]
+	}
+	return -10 * math.Log10(sum/float64(len(qual)))
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+	"github.com/will-rowe/archer/pkg/host"
+)
+
+// TestGetMeanQuality
+func TestGetMeanQuality(t *testing.T) {
+	tests := []struct {
+		qual string
+		mean float64
+	}{
+		{"", 0},
+		{"IIII", 40},
+		{"++++", 10},
+		{"+++5", 10 * math.Log10(4.0/0.31)},
+	}
+	for i, test := range tests {
+		if mean := getMeanQuality(test.qual); math.Abs(mean-test.mean) > 1e-9 {
+			t.Fatalf("test %d: wanted mean quality %f, got %f", i, test.mean, mean)
+		}
+	}
+}
+
+// TestQualityTrim
+func TestQualityTrim(t *testing.T) {
+	tests := []struct {
+		qual    string
+		window  int
+		quality float64
+		keep    int
+	}{
+		{"", 4, 20, 0},
+		{"IIIIIIII", 4, 20, 8},
+		{"IIII####", 4, 20, 3},
+		{"IIII####", 1, 20, 4},
+		{"####IIII", 4, 20, 0},
+		{"II#", 4, 20, 3},
+		{"I##", 4, 20, 0},
+	}
+	for i, test := range tests {
+		if keep := qualityTrim(test.qual, test.window, test.quality); keep != test.keep {
+			t.Fatalf("test %d: wanted to keep %d bases, got %d", i, test.keep, keep)
+		}
+	}
+	for i, request := range []*api.ProcessRequest{{TrimWindow: -1}, {TrimQuality: -1}, {TrimQuality: maxPhred + 1}} {
+		if err := checkFilters(request); err == nil {
+			t.Fatalf("test %d: bad trim passed the check", i)
+		}
+	}
+
+	// a request setting half of the trim needs the server to set the other half
+	halfTrims := []struct {
+		server  filterDefaults
+		request *api.ProcessRequest
+		valid   bool
+	}{
+		{filterDefaults{}, &api.ProcessRequest{}, true},
+		{filterDefaults{}, &api.ProcessRequest{TrimWindow: 10, TrimQuality: 20}, true},
+		{filterDefaults{}, &api.ProcessRequest{TrimWindow: 10}, false},
+		{filterDefaults{}, &api.ProcessRequest{TrimQuality: 20}, false},
+		{filterDefaults{trimQuality: 20}, &api.ProcessRequest{TrimWindow: 10}, true},
+		{filterDefaults{trimWindow: 10}, &api.ProcessRequest{TrimQuality: 20}, true},
+	}
+	for i, test := range halfTrims {
+		a := &Archer{filters: test.server}
+		if err := a.checkQualityTrim(test.request); (err == nil) != test.valid {
+			t.Fatalf("test %d: wanted valid == %v, got error: %v", i, test.valid, err)
+		}
+	}
+}
+
+// TestGetLengthBounds
+func TestGetLengthBounds(t *testing.T) {
+	filterDb := "./tmp-bounds"
+	defer os.RemoveAll(filterDb)
+	a, shutdown := newTestArcher(t, filterDb, SetLengthTolerance(0.1))
+	defer shutdown()
+	tests := []struct {
+		request  *api.ProcessRequest
+		min, max int32
+	}{
+		{&api.ProcessRequest{}, 900, 1100},
+		{&api.ProcessRequest{LengthTolerance: 0.5}, 500, 1500},
+		{&api.ProcessRequest{MinLength: 200}, 200, 1100},
+		{&api.ProcessRequest{LengthTolerance: 0.5, MaxLength: 2000}, 500, 2000},
+	}
+	for i, test := range tests {
+		if min, max := a.getLengthBounds(test.request, 1000); min != test.min || max != test.max {
+			t.Fatalf("test %d: wanted bounds %d-%d, got %d-%d", i, test.min, test.max, min, max)
+		}
+	}
+	for i, request := range []*api.ProcessRequest{{MinLength: -1}, {MinLength: 10, MaxLength: 5}, {LengthTolerance: 1}, {MinMeanQuality: maxPhred + 1}} {
+		if err := checkFilters(request); err == nil {
+			t.Fatalf("test %d: bad filters passed the check", i)
+		}
+	}
+}
+
+// TestProcessFilters will check that reads are filtered
+// using the request filters and that the filters used
+// are recorded for the sample.
+func TestProcessFilters(t *testing.T) {
+	filterDb := "./tmp-filter"
+	defer os.RemoveAll(filterDb)
+	a, shutdown := newTestArcher(t, filterDb, SetMinMeanQuality(5))
+	defer shutdown()
+
+	// write reads for each filter outcome
+	ref := getTestReference(t)
+	fastq := filepath.Join(filterDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{
+		{ref[30:424], 'I'},   // amplicon 1
+		{ref[350:774], 'I'},  // amplicon 2
+		{ref[700:1124], '5'}, // amplicon 3, mean quality 20
+		{ref[350:450], 'I'},  // too short
+		{ref[0:1000], 'I'},   // too long
+	})
+
+	// process the sample with a higher quality filter
+	request := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "filtered",
+		InputFASTQfiles: []string{fastq},
+		Scheme:          "test",
+		SchemeVersion:   1,
+		MinMeanQuality:  30,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	sample := waitForSample(t, a, "filtered")
+	if sample.GetState() != api.State_SUCCESS {
+		t.Fatalf("sample did not process: %v", sample.GetErrors())
+	}
+	stats := sample.GetProcessStats()
+	if stats.GetTotalReads() != 5 || stats.GetKeptReads() != 2 {
+		t.Fatalf("wanted 2 of 5 reads kept, got %d of %d", stats.GetKeptReads(), stats.GetTotalReads())
+	}
+	if stats.GetAmpliconCoverage()["1"] != 1 || stats.GetAmpliconCoverage()["2"] != 1 || stats.GetAmpliconCoverage()["3"] != 0 {
+		t.Fatalf("unexpected amplicon coverage: %v", stats.GetAmpliconCoverage())
+	}
+	if stats.GetLengthMin() != 332 || stats.GetLengthMax() != 496 || stats.GetMinMeanQuality() != 30 || stats.GetScoreThreshold() != DefaultJaccardThreshold {
+		t.Fatalf("filters used were not recorded: %v", stats)
+	}
+
+	// check the filter counts
+	if stats.GetDroppedReads().GetLength() != 2 || stats.GetDroppedReads().GetQuality() != 1 || stats.GetDroppedReads().GetNoHit() != 0 || stats.GetTrimmedReads() != 0 {
+		t.Fatalf("unexpected dropped read counts: %v", stats.GetDroppedReads())
+	}
+	if len(stats.GetQualityHistogram()) != qualityBins || stats.GetQualityHistogram()[40] != 4 || stats.GetQualityHistogram()[20] != 1 {
+		t.Fatalf("unexpected quality histogram: %v", stats.GetQualityHistogram())
+	}
+	if stats.GetKeptQualityHistogram()[40] != 2 || stats.GetKeptQualityHistogram()[20] != 0 {
+		t.Fatalf("unexpected kept quality histogram: %v", stats.GetKeptQualityHistogram())
+	}
+
+	// a read with a low quality tail is too long unless it is trimmed
+	seq := ref[350:774] + ref[0:150]
+	qual := strings.Repeat("I", 424) + strings.Repeat("#", 150)
+	trimFASTQ := filepath.Join(filterDb, "trim.fastq")
+	if err := ioutil.WriteFile(trimFASTQ, []byte(fmt.Sprintf("@read0\n%s\n+\n%s\n", seq, qual)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	request = &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "trimmed",
+		InputFASTQfiles: []string{trimFASTQ},
+		Scheme:          "test",
+		SchemeVersion:   1,
+		TrimWindow:      10,
+		TrimQuality:     20,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	sample = waitForSample(t, a, "trimmed")
+	stats = sample.GetProcessStats()
+	if stats.GetTrimmedReads() != 1 || stats.GetKeptReads() != 1 || stats.GetAmpliconCoverage()["2"] != 1 {
+		t.Fatalf("read was not trimmed and kept: %v", stats)
+	}
+	if stats.GetTrimWindow() != 10 || stats.GetTrimQuality() != 20 || stats.GetKeptQualityHistogram()[40] != 1 {
+		t.Fatalf("trim used was not recorded: %v", stats)
+	}
+}
+
+// TestProcessDepthCap will check that the number of
+// reads kept for each amplicon can be capped.
+func TestProcessDepthCap(t *testing.T) {
+	depthDb := "./tmp-depth"
+	defer os.RemoveAll(depthDb)
+	a, shutdown := newTestArcher(t, depthDb, SetMaxAmpliconDepth(3))
+	defer shutdown()
+
+	// write 5 reads for amplicon 1 and 2 for amplicon 2
+	ref := getTestReference(t)
+	reads := []testRead{}
+	for i := 0; i < 5; i++ {
+		reads = append(reads, testRead{ref[30:424], 'I'})
+	}
+	reads = append(reads, testRead{ref[350:774], 'I'}, testRead{ref[350:774], 'I'})
+	fastq := filepath.Join(depthDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, reads)
+
+	// use the server cap, then a request cap
+	tests := []struct {
+		depth      int32
+		normalised map[string]int32
+	}{
+		{0, map[string]int32{"1": 3, "2": 2, "3": 0}},
+		{1, map[string]int32{"1": 1, "2": 1, "3": 0}},
+	}
+	for i, test := range tests {
+		sampleID := fmt.Sprintf("depth-%d", i)
+		request := &api.ProcessRequest{
+			ApiVersion:       apiVersion,
+			SampleID:         sampleID,
+			InputFASTQfiles:  []string{fastq},
+			Scheme:           "test",
+			SchemeVersion:    1,
+			MaxAmpliconDepth: test.depth,
+		}
+		if _, err := a.Process(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		sample := waitForSample(t, a, sampleID)
+		if sample.GetState() != api.State_SUCCESS {
+			t.Fatalf("test %d: sample did not process: %v", i, sample.GetErrors())
+		}
+		stats := sample.GetProcessStats()
+		kept := int32(0)
+		for amplicon, depth := range test.normalised {
+			if stats.GetNormalisedCoverage()[amplicon] != depth {
+				t.Fatalf("test %d: wanted normalised coverage %v, got %v", i, test.normalised, stats.GetNormalisedCoverage())
+			}
+			kept += depth
+		}
+		if stats.GetAmpliconCoverage()["1"] != 5 || stats.GetAmpliconCoverage()["2"] != 2 {
+			t.Fatalf("test %d: raw coverage was capped: %v", i, stats.GetAmpliconCoverage())
+		}
+		if stats.GetKeptReads() != kept || stats.GetDroppedReads().GetDepthCap() != 7-kept || len(readUpload(t, depthDb, sampleID)) != int(kept) {
+			t.Fatalf("test %d: wanted %d reads kept, got %d (%d capped)", i, kept, stats.GetKeptReads(), stats.GetDroppedReads().GetDepthCap())
+		}
+	}
+	if err := checkFilters(&api.ProcessRequest{MaxAmpliconDepth: -1}); err == nil {
+		t.Fatal("negative depth cap passed the check")
+	}
+}
+
+// TestProcessHostDepletion will check that reads
+// matching the host index are removed.
+func TestProcessHostDepletion(t *testing.T) {
+	hostDb := "./tmp-host"
+	defer os.RemoveAll(hostDb)
+	if err := os.MkdirAll(hostDb, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// build a host index that shares amplicon 3 with the scheme
+	ref := getTestReference(t)
+	idx, err := host.Build(strings.NewReader(">host\n"+ref[650:1150]+"\n"), host.DefaultKmerSize, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostIndex := filepath.Join(hostDb, "host.idx")
+	fh, err := os.Create(hostIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Write(fh); err != nil {
+		t.Fatal(err)
+	}
+	fh.Close()
+	a, shutdown := newTestArcher(t, hostDb, SetHostIndex(hostIndex, host.DefaultThreshold))
+	defer shutdown()
+
+	// process reads for each amplicon
+	fastq := filepath.Join(hostDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[30:424], 'I'}, {ref[350:774], 'I'}, {ref[700:1124], 'I'}})
+	request := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "host",
+		InputFASTQfiles: []string{fastq},
+		Scheme:          "test",
+		SchemeVersion:   1,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	sample := waitForSample(t, a, "host")
+	if sample.GetState() != api.State_SUCCESS {
+		t.Fatalf("sample did not process: %v", sample.GetErrors())
+	}
+	stats := sample.GetProcessStats()
+	if stats.GetKeptReads() != 2 || stats.GetDroppedReads().GetHost() != 1 || stats.GetAmpliconCoverage()["3"] != 0 {
+		t.Fatalf("host read was not removed: %v", stats)
+	}
+	if stats.GetHostThreshold() != host.DefaultThreshold {
+		t.Fatalf("host threshold was not recorded: %v", stats.GetHostThreshold())
+	}
+	if _, _, err := NewArcher(SetHostIndex(filepath.Join(hostDb, "missing.idx"), host.DefaultThreshold)); err == nil {
+		t.Fatal("expected missing host index to fail")
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/grailbio/bio/encoding/fastq"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/will-rowe/archer/pkg/amplicons"
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+const (
+
+	// stagingDir is the directory in the db path used to stage reads for the QC gate
+	stagingDir = "staging"
+
+	// quarantinePrefix is added to the upload key for samples that fail the QC gate
+	quarantinePrefix = "quarantine/"
+)
+
+// qcGateActions maps the launch option names to
+// whether samples that fail the QC gate are
+// uploaded to the quarantine prefix.
+var qcGateActions = map[string]bool{
+	"block":      false,
+	"quarantine": true,
+}
+
+// qcGate is checked once the reads for a sample have
+// been filtered, before they are uploaded. A threshold
+// of 0 is not checked.
+type qcGate struct {
+	minKeptReads int32   // the minimum number of kept reads
+	minAmplicons int32   // the minimum number of amplicons at the QC depth
+	maxOffTarget float32 // the maximum off-target fraction
+	quarantine   bool    // upload failing samples to the quarantine prefix, rather than not at all
+}
+
+// enabled returns true if the gate has any thresholds.
+func (g qcGate) enabled() bool {
+	return g.minKeptReads > 0 || g.minAmplicons > 0 || g.maxOffTarget > 0
+}
+
+// check returns the reasons a sample fails the gate,
+// which is empty if the sample passes. The sample QC
+// report must already be set.
+func (g qcGate) check(sample *api.SampleInfo) []string {
+	failures := []string{}
+	if keptReads := sample.GetProcessStats().GetKeptReads(); g.minKeptReads > 0 && keptReads < g.minKeptReads {
+		failures = append(failures, fmt.Sprintf("kept reads below the gate minimum (%d < %d)", keptReads, g.minKeptReads))
+	}
+	if passed := sample.GetSampleQC().GetPassedAmplicons(); g.minAmplicons > 0 && passed < g.minAmplicons {
+		failures = append(failures, fmt.Sprintf("amplicons at %dx or more below the gate minimum (%d < %d)", sample.GetSampleQC().GetMinDepth(), passed, g.minAmplicons))
+	}
+	if offTarget := sample.GetSampleQC().GetOffTarget(); g.maxOffTarget > 0 && offTarget > g.maxOffTarget {
+		failures = append(failures, fmt.Sprintf("off-target fraction above the gate maximum (%.2f > %.2f)", offTarget, g.maxOffTarget))
+	}
+	return failures
+}
+
+// getOffTarget returns the fraction of the reads passing
+// the length and quality filters that were not matched
+// to an amplicon or were removed as host reads.
+func getOffTarget(stats *api.SampleStats) float32 {
+	dropped := stats.GetDroppedReads()
+	checked := stats.GetTotalReads() - dropped.GetLength() - dropped.GetQuality()
+	if checked <= 0 {
+		return 0.0
+	}
+	return float32(dropped.GetNoHit()+dropped.GetHost()) / float32(checked)
+}
+
+// stageSample will write the kept reads for a sample to
+// a local staging file and then check the sample against
+// the QC gate. Samples that pass are uploaded as usual,
+// while failing samples are either not uploaded or are
+// uploaded under the quarantine prefix. It returns the
+// upload location (empty if not uploaded) and any error.
+// Nothing is uploaded if the filter hit a read error.
+func (a *Archer) stageSample(ctx context.Context, sample *api.SampleInfo, as *amplicons.AmpliconSet, tracker *progressTracker, readChan <-chan fastq.Read, filterDone <-chan struct{}, readErr *error, scrubber *headerScrubber, key string) (string, error) {
+
+	// create the staging file, draining the filter if it can't be created
+	staged, err := a.newStagingFile(sample.GetSampleID())
+	if err != nil {
+		for range readChan {
+		}
+		<-filterDone
+		scrubber.close()
+		return "", err
+	}
+
+	// the staged reads are kept until the upload succeeds or runs out of retries
+	defer os.Remove(staged.Name())
+
+	// stage the reads
+	err = writeReads(ctx, readChan, staged, scrubber)
+	<-filterDone
+	if closeErr := staged.Close(); closeErr != nil && err == nil {
+		err = fmt.Errorf("could not stage reads: %w", closeErr)
+	}
+	if err != nil {
+		return "", err
+	}
+	if *readErr != nil {
+		return "", nil
+	}
+
+	// check the gate before deciding where the reads go
+	a.summariseSample(sample, as)
+	sample.SampleQC.GateFailures = a.qcGate.check(sample)
+	if len(sample.SampleQC.GetGateFailures()) != 0 {
+		if !a.qcGate.quarantine {
+			log.Warnf("%v failed the QC gate and will not be uploaded: %v", sample.GetSampleID(), strings.Join(sample.SampleQC.GetGateFailures(), "; "))
+			return "", nil
+		}
+		log.Warnf("%v failed the QC gate and will be quarantined: %v", sample.GetSampleID(), strings.Join(sample.SampleQC.GetGateFailures(), "; "))
+		key = quarantinePrefix + key
+		sample.SampleQC.Quarantined = true
+	}
+
+	// upload the staged reads, retrying just the upload if it hits a transient error
+	return a.retryUpload(ctx, sample, func() (string, error) {
+		fh, err := os.Open(staged.Name())
+		if err != nil {
+			return "", fmt.Errorf("could not open staged reads: %w", err)
+		}
+		defer fh.Close()
+		atomic.StoreInt64(&tracker.bytesUploaded, 0)
+		return a.storage.Upload(ctx, &countingReader{reader: fh, count: &tracker.bytesUploaded}, key)
+	})
+}
+
+// newStagingFile creates a file in the staging
+// directory to hold the kept reads for a sample.
+func (a *Archer) newStagingFile(sampleID string) (*os.File, error) {
+	if err := os.MkdirAll(a.stagingDir, 0700); err != nil {
+		return nil, fmt.Errorf("could not create staging directory: %w", err)
+	}
+	fh, err := ioutil.TempFile(a.stagingDir, sampleFileName(sampleID)+"-*.fastq.gz")
+	if err != nil {
+		return nil, fmt.Errorf("could not create staging file: %w", err)
+	}
+	return fh, nil
+}
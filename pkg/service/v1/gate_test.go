@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// TestProcessQCGate will check that samples failing the
+// QC gate are not uploaded, or are quarantined.
+func TestProcessQCGate(t *testing.T) {
+	gateDb := "./tmp-gate"
+	defer os.RemoveAll(gateDb)
+	if err := os.MkdirAll(gateDb, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := NewArcher(SetQCGate(1, 0, 0, "drop")); err == nil {
+		t.Fatal("expected unknown QC gate action to fail")
+	}
+	if _, _, err := NewArcher(SetQCGate(1, 0, 1.5, "block")); err == nil {
+		t.Fatal("expected bad off-target fraction to fail")
+	}
+
+	// write a sample with 4 amplicon reads and 1 off-target read,
+	// and a sample with 1 amplicon read and 2 off-target reads
+	ref := getTestReference(t)
+	offTarget := testRead{strings.Repeat("ACGTTGCA", 50), 'I'}
+	good := filepath.Join(gateDb, "good.fastq")
+	writeTestFASTQ(t, good, []testRead{{ref[30:424], 'I'}, {ref[30:424], 'I'}, {ref[350:774], 'I'}, {ref[700:1124], 'I'}, offTarget})
+	bad := filepath.Join(gateDb, "bad.fastq")
+	writeTestFASTQ(t, bad, []testRead{{ref[30:424], 'I'}, offTarget, offTarget})
+	tests := []struct {
+		action      string
+		sampleID    string
+		fastq       string
+		state       api.State
+		failures    int
+		upload      string
+		quarantined bool
+	}{
+		{"block", "good", good, api.State_SUCCESS, 0, "good.fastq.gz", false},
+		{"block", "bad", bad, api.State_FAILED_QC, 2, "", false},
+		{"quarantine", "bad", bad, api.State_FAILED_QC, 2, "quarantine/bad.fastq.gz", true},
+	}
+	for i, test := range tests {
+		db := filepath.Join(gateDb, test.sampleID+"-"+test.action)
+		staging := filepath.Join(db, "staged")
+		a, shutdown := newTestArcher(t, db, SetQCGate(3, 0, 0.5, test.action), SetStagingDir(staging))
+		request := &api.ProcessRequest{
+			ApiVersion:      apiVersion,
+			SampleID:        test.sampleID,
+			InputFASTQfiles: []string{test.fastq},
+			Scheme:          "test",
+			SchemeVersion:   1,
+		}
+		if _, err := a.Process(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		sample := waitForSample(t, a, test.sampleID)
+		shutdown()
+		if sample.GetState() != test.state {
+			t.Fatalf("test %d: wanted %v, got %v (%v)", i, test.state, sample.GetState(), sample.GetErrors())
+		}
+		qc := sample.GetSampleQC()
+		if len(qc.GetGateFailures()) != test.failures || qc.GetQuarantined() != test.quarantined {
+			t.Fatalf("test %d: wanted %d gate failures (quarantined = %v), got %v", i, test.failures, test.quarantined, qc)
+		}
+
+		// check the upload and that the staged reads were removed
+		uploads := []string{}
+		filepath.Walk(filepath.Join(db, "uploads"), func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				rel, _ := filepath.Rel(filepath.Join(db, "uploads"), path)
+				uploads = append(uploads, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		switch {
+		case len(test.upload) == 0 && (len(uploads) != 0 || len(sample.GetEndpoint()) != 0):
+			t.Fatalf("test %d: failing sample was uploaded: %v", i, uploads)
+		case len(test.upload) != 0 && (len(uploads) != 1 || uploads[0] != test.upload || !strings.HasSuffix(sample.GetEndpoint(), test.upload)):
+			t.Fatalf("test %d: wanted upload %v, got %v (%v)", i, test.upload, uploads, sample.GetEndpoint())
+		}
+		if staged, err := ioutil.ReadDir(staging); err != nil || len(staged) != 0 {
+			t.Fatalf("test %d: staged reads were not removed: %v", i, err)
+		}
+	}
+}
+
+// TestProcessQCGateRetry will check that a staged sample
+// only retries the upload after a transient error, rather
+// than filtering and staging the reads again.
+func TestProcessQCGateRetry(t *testing.T) {
+	gateDb := "./tmp-gate-retry"
+	defer os.RemoveAll(gateDb)
+	staging := filepath.Join(gateDb, "staged")
+	a, shutdown := newTestArcher(t, gateDb, SetQCGate(1, 0, 0, "block"), SetStagingDir(staging))
+	defer shutdown()
+
+	// remove the input when the first upload fails, so that filtering the reads again would error
+	ref := getTestReference(t)
+	fastq := filepath.Join(gateDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[30:424], 'I'}, {ref[350:774], 'I'}})
+	storage := &flakyStorage{Storage: a.storage, failures: 1}
+	storage.onFail = func() {
+		if staged, err := ioutil.ReadDir(staging); err != nil || len(staged) != 1 {
+			t.Errorf("staged reads were not kept for the retry: %v", err)
+		}
+		os.Remove(fastq)
+	}
+	a.storage = storage
+	request := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "retry",
+		InputFASTQfiles: []string{fastq},
+		Scheme:          "test",
+		SchemeVersion:   1,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	sample := waitForSample(t, a, "retry")
+	if sample.GetState() != api.State_SUCCESS || sample.GetRetries() != 1 || storage.uploads != 2 {
+		t.Fatalf("upload was not retried: %v after %d retries and %d uploads (%v)", sample.GetState(), sample.GetRetries(), storage.uploads, sample.GetErrors())
+	}
+	if stats := sample.GetProcessStats(); stats.GetTotalReads() != 2 || stats.GetKeptReads() != 2 {
+		t.Fatalf("wanted 2 of 2 reads kept, got %d of %d", stats.GetKeptReads(), stats.GetTotalReads())
+	}
+	if reads := readUpload(t, gateDb, "retry"); len(reads) != 2 {
+		t.Fatalf("wanted 2 uploaded reads, got %d", len(reads))
+	}
+	if staged, err := ioutil.ReadDir(staging); err != nil || len(staged) != 0 {
+		t.Fatalf("staged reads were not removed: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/will-rowe/archer/pkg/amplicons"
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// GetInfo will respond to GetInfoRequests by streaming pages of
+// samples from the Archer db that match the request filters.
+func (a *Archer) GetInfo(request *api.GetInfoRequest, stream api.Archer_GetInfoServer) error {
+	log.Info("get info request received")
+
+	// check we have received a supported API request
+	if err := a.checkAPI(request.GetApiVersion()); err != nil {
+		return err
+	}
+	if request.GetPageSize() < 0 || request.GetLimit() < 0 {
+		return status.Errorf(codes.InvalidArgument, "pageSize and limit must be >= 0")
+	}
+	pageSize := int(request.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	// get the filter ready
+	filter, err := newInfoFilter(request, a.manifest)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf("bad request: %v", err))
+	}
+
+	// collect the sample IDs to check, in order so that the cursor can be used
+	var keys []string
+	if len(request.GetSampleIDs()) != 0 {
+		keys = append(keys, request.GetSampleIDs()...)
+	} else {
+		keys = a.getSampleIDs()
+	}
+	sort.Strings(keys)
+
+	// send matching samples a page at a time
+	page := make([]*api.SampleInfo, 0, pageSize)
+	pageEnd, matches := "", 0
+	for i, key := range keys {
+		if (i > 0 && key == keys[i-1]) || key <= request.GetCursor() {
+			continue
+		}
+		if !a.db.Has([]byte(key)) {
+			continue
+		}
+		sample, err := a.getSample(key)
+		if err != nil {
+			return status.Errorf(codes.Internal, fmt.Sprintf("could not read sample from db: %v", err))
+		}
+		if !filter.match(sample) {
+			continue
+		}
+
+		// a full page (or one that hit the limit) is only sent once there's
+		// another match, so that its cursor is only set if there are more
+		limitReached := request.GetLimit() != 0 && matches == int(request.GetLimit())
+		if len(page) == pageSize || limitReached {
+			if err := a.sendInfoPage(stream, page, pageEnd); err != nil {
+				return err
+			}
+			if limitReached {
+				return nil
+			}
+			page = make([]*api.SampleInfo, 0, pageSize)
+		}
+		page = append(page, sample)
+		pageEnd = key
+		matches++
+	}
+
+	// send the last page, signalling the end of the matches
+	return a.sendInfoPage(stream, page, "")
+}
+
+// sendInfoPage sends a page of samples to a
+// GetInfo stream.
+func (a *Archer) sendInfoPage(stream api.Archer_GetInfoServer, page []*api.SampleInfo, nextCursor string) error {
+	if err := stream.Send(&api.GetInfoResponse{
+		ApiVersion: a.version,
+		Samples:    page,
+		NextCursor: nextCursor,
+	}); err != nil {
+		log.Errorf("get info request failed to return a message: %v", err)
+		return err
+	}
+	return nil
+}
+
+// infoFilter holds the converted filters
+// from a GetInfoRequest.
+type infoFilter struct {
+	states        map[api.State]struct{}
+	scheme        string
+	startedAfter  *time.Time
+	startedBefore *time.Time
+	endedAfter    *time.Time
+	endedBefore   *time.Time
+}
+
+// newInfoFilter will convert a GetInfoRequest
+// into an infoFilter. Any scheme alias is resolved
+// to the scheme tag stored for each sample, using
+// the manifest if one is loaded.
+func newInfoFilter(request *api.GetInfoRequest, manifest *api.Manifest) (*infoFilter, error) {
+	f := &infoFilter{
+		states: make(map[api.State]struct{}),
+		scheme: request.GetScheme(),
+	}
+	if len(f.scheme) != 0 && manifest != nil {
+		if schemeTag, err := amplicons.CheckManifest(manifest, f.scheme, 0); err == nil {
+			f.scheme = schemeTag
+		}
+	}
+	for _, state := range request.GetStates() {
+		f.states[state] = struct{}{}
+	}
+	var err error
+	if f.startedAfter, err = convertTimestamp(request.GetStartedAfter()); err != nil {
+		return nil, err
+	}
+	if f.startedBefore, err = convertTimestamp(request.GetStartedBefore()); err != nil {
+		return nil, err
+	}
+	if f.endedAfter, err = convertTimestamp(request.GetEndedAfter()); err != nil {
+		return nil, err
+	}
+	if f.endedBefore, err = convertTimestamp(request.GetEndedBefore()); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// match returns true if the sample passes
+// all of the filters.
+func (f *infoFilter) match(sample *api.SampleInfo) bool {
+	if len(f.states) != 0 {
+		if _, ok := f.states[sample.GetState()]; !ok {
+			return false
+		}
+	}
+	if len(f.scheme) != 0 && sample.GetProcessRequest().GetScheme() != f.scheme {
+		return false
+	}
+	if !inWindow(sample.GetStartTime(), f.startedAfter, f.startedBefore) {
+		return false
+	}
+	if !inWindow(sample.GetEndTime(), f.endedAfter, f.endedBefore) {
+		return false
+	}
+	return true
+}
+
+// inWindow checks a timestamp falls within
+// the [after, before) window. Unset bounds
+// are ignored, but an unset timestamp will
+// not match any bound.
+func inWindow(ts *timestamp.Timestamp, after, before *time.Time) bool {
+	if after == nil && before == nil {
+		return true
+	}
+	if ts == nil {
+		return false
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return false
+	}
+	if after != nil && t.Before(*after) {
+		return false
+	}
+	if before != nil && !t.Before(*before) {
+		return false
+	}
+	return true
+}
+
+// convertTimestamp is a helper function to
+// convert an optional proto timestamp.
+func convertTimestamp(ts *timestamp.Timestamp) (*time.Time, error) {
+	if ts == nil {
+		return nil, nil
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// infoStream is a minimal Archer_GetInfoServer
+// that collects the sent responses.
+type infoStream struct {
+	grpc.ServerStream
+	responses []*api.GetInfoResponse
+}
+
+func (s *infoStream) Send(resp *api.GetInfoResponse) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+// TestArcher_GetInfo will check that GetInfo filters
+// and pages through the sample records.
+func TestArcher_GetInfo(t *testing.T) {
+	infoDb := "./tmp-info"
+	defer os.RemoveAll(infoDb)
+	aInterface, shutdown, err := NewArcher(SetDb(infoDb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := aInterface.(*Archer)
+
+	// add some samples
+	for i, id := range []string{"s1", "s2", "s3", "s4", "s5"} {
+		sample, err := NewSample(SetID(id), SetRequest(&api.ProcessRequest{Scheme: "sars-cov-2"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 0 {
+			sample.State = api.State_SUCCESS
+		}
+		if err := a.addSample(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// filter on state and page the results
+	stream := &infoStream{}
+	req := &api.GetInfoRequest{ApiVersion: apiVersion, States: []api.State{api.State_SUCCESS}, PageSize: 2}
+	if err := a.GetInfo(req, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.responses) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(stream.responses))
+	}
+	if stream.responses[0].GetNextCursor() != "s3" || len(stream.responses[1].GetNextCursor()) != 0 {
+		t.Fatalf("unexpected cursors: %v and %v", stream.responses[0].GetNextCursor(), stream.responses[1].GetNextCursor())
+	}
+
+	// resume from a cursor with a limit
+	stream = &infoStream{}
+	req = &api.GetInfoRequest{ApiVersion: apiVersion, Cursor: "s1", Limit: 2}
+	if err := a.GetInfo(req, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.responses) != 1 || len(stream.responses[0].GetSamples()) != 2 || stream.responses[0].GetSamples()[0].GetSampleID() != "s2" || stream.responses[0].GetNextCursor() != "s3" {
+		t.Fatal("cursor and limit not applied to query")
+	}
+
+	// a last page that is exactly full, or a limit that matches everything, should end the matches
+	tests := []struct {
+		pageSize, limit int32
+		pages           int
+	}{
+		{5, 0, 1},
+		{1, 5, 5},
+		{0, 5, 1},
+	}
+	for i, test := range tests {
+		stream = &infoStream{}
+		if err := a.GetInfo(&api.GetInfoRequest{ApiVersion: apiVersion, PageSize: test.pageSize, Limit: test.limit}, stream); err != nil {
+			t.Fatal(err)
+		}
+		if len(stream.responses) != test.pages || len(stream.responses[test.pages-1].GetNextCursor()) != 0 {
+			t.Fatalf("test %d: wanted %d pages with no cursor on the last, got %d: %v", i, test.pages, len(stream.responses), stream.responses)
+		}
+	}
+
+	// no matches still returns a response
+	stream = &infoStream{}
+	req = &api.GetInfoRequest{ApiVersion: apiVersion, Scheme: "missing"}
+	if err := a.GetInfo(req, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.responses) != 1 || len(stream.responses[0].GetSamples()) != 0 {
+		t.Fatal("expected a single empty response")
+	}
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGetInfoSchemeAlias will check that samples can
+// be filtered by a scheme alias from the manifest.
+func TestGetInfoSchemeAlias(t *testing.T) {
+	infoDb := "./tmp-info-alias"
+	defer os.RemoveAll(infoDb)
+	a, shutdown := newTestArcher(t, infoDb)
+	defer shutdown()
+
+	// samples are stored with the scheme tag from the manifest
+	for id, scheme := range map[string]string{"s1": "test", "s2": "test-segmented", "s3": "test"} {
+		sample, err := NewSample(SetID(id), SetRequest(&api.ProcessRequest{Scheme: scheme}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.addSample(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, scheme := range []string{"test", "test-scheme"} {
+		stream := &infoStream{}
+		if err := a.GetInfo(&api.GetInfoRequest{ApiVersion: apiVersion, Scheme: scheme}, stream); err != nil {
+			t.Fatal(err)
+		}
+		samples := stream.responses[0].GetSamples()
+		if len(samples) != 2 || samples[0].GetSampleID() != "s1" || samples[1].GetSampleID() != "s3" {
+			t.Fatalf("wrong samples for scheme %v: %v", scheme, samples)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grailbio/bio/encoding/fastq"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// headerPolicies maps the launch option names
+// to the read header policies.
+var headerPolicies = map[string]api.HeaderPolicy{
+	"keep":   api.HeaderPolicy_KEEP_HEADERS,
+	"index":  api.HeaderPolicy_INDEX_HEADERS,
+	"sample": api.HeaderPolicy_SAMPLE_HEADERS,
+}
+
+// headerMapSuffix is added to the sample ID to name
+// the local header mapping file for a sample
+const headerMapSuffix = ".headers.tsv"
+
+// parseHeaderPolicy will return the HeaderPolicy
+// for a policy name (keep, index or sample).
+func parseHeaderPolicy(policy string) (api.HeaderPolicy, error) {
+	p, ok := headerPolicies[policy]
+	if !ok {
+		return api.HeaderPolicy_DEFAULT_HEADERS, fmt.Errorf("unknown header policy: %v (must be keep, index or sample)", policy)
+	}
+	return p, nil
+}
+
+// getHeaderPolicy returns the read header policy for
+// a request, using the server setting if the request
+// does not set one.
+func (a *Archer) getHeaderPolicy(request *api.ProcessRequest) api.HeaderPolicy {
+	if request.GetHeaderPolicy() != api.HeaderPolicy_DEFAULT_HEADERS {
+		return request.GetHeaderPolicy()
+	}
+	return a.filters.headerPolicy
+}
+
+// getHeaderMapPath returns the path of the local header
+// mapping file for a sample, or an empty string if the
+// headers aren't mapped.
+func (a *Archer) getHeaderMapPath(sample *api.SampleInfo) string {
+	policy := sample.GetProcessStats().GetHeaderPolicy()
+	if len(a.headerMapDir) == 0 || policy == api.HeaderPolicy_KEEP_HEADERS {
+		return ""
+	}
+	return filepath.Join(a.headerMapDir, sampleFileName(sample.GetSampleID())+headerMapSuffix)
+}
+
+// headerScrubber rewrites the headers of kept reads
+// using a header policy and can record the original
+// headers in a local mapping file.
+type headerScrubber struct {
+	policy   api.HeaderPolicy
+	sampleID string
+	count    int
+	mapFile  *os.File
+	mapping  *bufio.Writer
+}
+
+// newHeaderScrubber returns a headerScrubber for a sample.
+// If a mapping path is given, the mapping file is created
+// (replacing any existing one).
+func newHeaderScrubber(policy api.HeaderPolicy, sampleID, mapPath string) (*headerScrubber, error) {
+	hs := &headerScrubber{
+		policy:   policy,
+		sampleID: sampleID,
+	}
+	if len(mapPath) == 0 || policy == api.HeaderPolicy_KEEP_HEADERS {
+		return hs, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(mapPath), 0700); err != nil {
+		return nil, fmt.Errorf("could not create header map directory: %w", err)
+	}
+	fh, err := os.OpenFile(mapPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not create header map: %w", err)
+	}
+	hs.mapFile = fh
+	hs.mapping = bufio.NewWriter(fh)
+	return hs, nil
+}
+
+// scrub will rewrite the header of a read, recording
+// the original header in any mapping file. The header
+// is also removed from the read's separator line.
+func (hs *headerScrubber) scrub(read *fastq.Read) error {
+	hs.count++
+	var id string
+	switch hs.policy {
+	case api.HeaderPolicy_INDEX_HEADERS:
+		id = fmt.Sprintf("%d", hs.count)
+	case api.HeaderPolicy_SAMPLE_HEADERS:
+		id = fmt.Sprintf("%s_%d", hs.sampleID, hs.count)
+	default:
+		return nil
+	}
+	if hs.mapping != nil {
+		if _, err := fmt.Fprintf(hs.mapping, "%s\t%s\n", id, strings.TrimPrefix(read.ID, "@")); err != nil {
+			return fmt.Errorf("could not write header map: %w", err)
+		}
+	}
+	read.ID = "@" + id
+	read.Unk = "+"
+	return nil
+}
+
+// close will flush and close any mapping file.
+func (hs *headerScrubber) close() error {
+	if hs.mapFile == nil {
+		return nil
+	}
+	if err := hs.mapping.Flush(); err != nil {
+		hs.mapFile.Close()
+		return fmt.Errorf("could not write header map: %w", err)
+	}
+	return hs.mapFile.Close()
+}
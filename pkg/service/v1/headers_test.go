@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// TestProcessHeaders will check the read headers
+// written for each header policy.
+func TestProcessHeaders(t *testing.T) {
+	headerDb := "./tmp-headers"
+	defer os.RemoveAll(headerDb)
+	headerMaps := filepath.Join(headerDb, "headermaps")
+	a, shutdown := newTestArcher(t, headerDb, SetHeaderMapDir(headerMaps))
+	defer shutdown()
+	if _, err := parseHeaderPolicy("drop"); err == nil {
+		t.Fatal("expected unknown header policy to fail")
+	}
+
+	// the test reads are named read0, read1...
+	ref := getTestReference(t)
+	fastq := filepath.Join(headerDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[0:1000], 'I'}, {ref[30:424], 'I'}, {ref[350:774], 'I'}})
+	tests := []struct {
+		policy  api.HeaderPolicy
+		ids     []string
+		mapping string
+	}{
+		{api.HeaderPolicy_DEFAULT_HEADERS, []string{"@read1", "@read2"}, ""},
+		{api.HeaderPolicy_INDEX_HEADERS, []string{"@1", "@2"}, "1\tread1\n2\tread2\n"},
+		{api.HeaderPolicy_SAMPLE_HEADERS, []string{"@SAMPLE_HEADERS_1", "@SAMPLE_HEADERS_2"}, "SAMPLE_HEADERS_1\tread1\nSAMPLE_HEADERS_2\tread2\n"},
+	}
+	for i, test := range tests {
+		sampleID := test.policy.String()
+		request := &api.ProcessRequest{
+			ApiVersion:      apiVersion,
+			SampleID:        sampleID,
+			InputFASTQfiles: []string{fastq},
+			Scheme:          "test",
+			SchemeVersion:   1,
+			HeaderPolicy:    test.policy,
+		}
+		if _, err := a.Process(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		sample := waitForSample(t, a, sampleID)
+		if sample.GetState() != api.State_SUCCESS {
+			t.Fatalf("test %d: sample did not process: %v", i, sample.GetErrors())
+		}
+
+		// check the uploaded headers
+		reads := readUpload(t, headerDb, sampleID)
+		if len(reads) != len(test.ids) {
+			t.Fatalf("test %d: wanted %d reads, got %d", i, len(test.ids), len(reads))
+		}
+		for j, read := range reads {
+			if read.ID != test.ids[j] || read.Unk != "+" {
+				t.Fatalf("test %d: wanted header %v, got %v", i, test.ids[j], read.ID)
+			}
+		}
+
+		// check the local header mapping
+		headerMap := sample.GetProcessStats().GetHeaderMap()
+		if len(test.mapping) == 0 {
+			if len(headerMap) != 0 {
+				t.Fatalf("test %d: unexpected header map: %v", i, headerMap)
+			}
+			continue
+		}
+		if headerMap != filepath.Join(headerMaps, sampleID+headerMapSuffix) {
+			t.Fatalf("test %d: header map not recorded: %v", i, headerMap)
+		}
+		mapping, err := ioutil.ReadFile(headerMap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(mapping) != test.mapping {
+			t.Fatalf("test %d: wanted header map %q, got %q", i, test.mapping, mapping)
+		}
+	}
+}
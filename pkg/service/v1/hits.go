@@ -0,0 +1,57 @@
+package service
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/will-rowe/archer/pkg/amplicons"
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// multiHitActions maps the launch option names
+// to the multi-hit actions.
+var multiHitActions = map[string]api.MultiHitAction{
+	"keep":     api.MultiHitAction_KEEP_MULTI_HIT,
+	"chimeric": api.MultiHitAction_DISCARD_CHIMERIC,
+	"all":      api.MultiHitAction_DISCARD_MULTI_HIT,
+}
+
+// parseMultiHitAction will return the MultiHitAction
+// for an action name (keep, chimeric or all).
+func parseMultiHitAction(action string) (api.MultiHitAction, error) {
+	a, ok := multiHitActions[action]
+	if !ok {
+		return api.MultiHitAction_DEFAULT_MULTI_HIT, fmt.Errorf("unknown multi-hit action: %v (must be keep, chimeric or all)", action)
+	}
+	return a, nil
+}
+
+// getMultiHit returns the multi-hit ratio and action
+// for a request, using the server settings for any
+// that the request does not set.
+func (a *Archer) getMultiHit(request *api.ProcessRequest) (float32, api.MultiHitAction) {
+	ratio, action := a.filters.multiHitRatio, a.filters.multiHitAction
+	if request.GetMultiHitRatio() != 0 {
+		ratio = request.GetMultiHitRatio()
+	}
+	if request.GetMultiHitAction() != api.MultiHitAction_DEFAULT_MULTI_HIT {
+		action = request.GetMultiHitAction()
+	}
+	return ratio, action
+}
+
+// checkMultiHit will flag a read as ambiguous or chimeric
+// from its ranked hits, counting it in the sample stats.
+// It returns true if the read should be discarded.
+func checkMultiHit(as *amplicons.AmpliconSet, hits []amplicons.Hit, stats *api.SampleStats) bool {
+	switch as.ClassifyHits(hits, float64(stats.GetMultiHitRatio())) {
+	case amplicons.AmbiguousHit:
+		atomic.AddInt32(&stats.AmbiguousReads, 1)
+		return stats.GetMultiHitAction() == api.MultiHitAction_DISCARD_MULTI_HIT
+	case amplicons.ChimericHit:
+		atomic.AddInt32(&stats.ChimericReads, 1)
+		return stats.GetMultiHitAction() == api.MultiHitAction_DISCARD_CHIMERIC || stats.GetMultiHitAction() == api.MultiHitAction_DISCARD_MULTI_HIT
+	default:
+		return false
+	}
+}
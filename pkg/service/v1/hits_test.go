@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// TestProcessMultiHits will check that ambiguous and
+// chimeric reads are counted and discarded according
+// to the multi-hit action.
+func TestProcessMultiHits(t *testing.T) {
+	multiDb := "./tmp-multihits"
+	defer os.RemoveAll(multiDb)
+	a, shutdown := newTestArcher(t, multiDb)
+	defer shutdown()
+	if _, err := parseMultiHitAction("none"); err == nil {
+		t.Fatal("expected unknown multi-hit action to fail")
+	}
+
+	// write a read for amplicon 1, a read from the overlap of
+	// amplicons 1 and 2, and a chimera of amplicons 1 and 3
+	// (which needs a lower score threshold to be assigned)
+	ref := getTestReference(t)
+	fastq := filepath.Join(multiDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[30:424], 'I'}, {ref[350:424], 'I'}, {ref[30:424] + ref[700:1124], 'I'}})
+	tests := []struct {
+		action  api.MultiHitAction
+		dropped int32
+	}{
+		{api.MultiHitAction_DEFAULT_MULTI_HIT, 0},
+		{api.MultiHitAction_DISCARD_CHIMERIC, 1},
+		{api.MultiHitAction_DISCARD_MULTI_HIT, 2},
+	}
+	for i, test := range tests {
+		sampleID := test.action.String()
+		request := &api.ProcessRequest{
+			ApiVersion:      apiVersion,
+			SampleID:        sampleID,
+			InputFASTQfiles: []string{fastq},
+			Scheme:          "test",
+			SchemeVersion:   1,
+			Scoring:         api.Scoring_CONTAINMENT,
+			ScoreThreshold:  0.4,
+			MinLength:       50,
+			MaxLength:       1000,
+			MultiHitAction:  test.action,
+		}
+		if _, err := a.Process(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		sample := waitForSample(t, a, sampleID)
+		if sample.GetState() != api.State_SUCCESS {
+			t.Fatalf("test %d: sample did not process: %v", i, sample.GetErrors())
+		}
+		stats := sample.GetProcessStats()
+		if stats.GetMultiHitRatio() != DefaultMultiHitRatio || stats.GetMultiHitAction() == api.MultiHitAction_DEFAULT_MULTI_HIT {
+			t.Fatalf("test %d: multi-hit settings not recorded: %v %v", i, stats.GetMultiHitRatio(), stats.GetMultiHitAction())
+		}
+		if stats.GetAmbiguousReads() != 1 || stats.GetChimericReads() != 1 {
+			t.Fatalf("test %d: wanted 1 ambiguous and 1 chimeric read, got %d and %d", i, stats.GetAmbiguousReads(), stats.GetChimericReads())
+		}
+		if stats.GetDroppedReads().GetMultiHit() != test.dropped || stats.GetKeptReads() != 3-test.dropped {
+			t.Fatalf("test %d: wanted %d reads discarded, got %d (%d kept)", i, test.dropped, stats.GetDroppedReads().GetMultiHit(), stats.GetKeptReads())
+		}
+	}
+	for i, request := range []*api.ProcessRequest{{MultiHitRatio: -0.1}, {MultiHitRatio: 1.1}, {MultiHitAction: 10}} {
+		if err := checkFilters(request); err == nil {
+			t.Fatalf("test %d: bad multi-hit setting passed the check", i)
+		}
+	}
+}
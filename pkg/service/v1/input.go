@@ -0,0 +1,139 @@
+package service
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fastqExtensions are the file extensions used to
+// find FASTQ files in input directories.
+var fastqExtensions = []string{".fastq", ".fq", ".fastq.gz", ".fq.gz"}
+
+// isFASTQ checks if a filename has a FASTQ file
+// extension (compressed or uncompressed).
+func isFASTQ(name string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range fastqExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandInputs will expand the input FASTQ entries of a
+// request into a list of FASTQ files. Entries can be
+// files, directories or glob patterns. Directories are
+// not searched recursively and only files with a FASTQ
+// extension are collected from them. Each file is only
+// returned once, in the order found.
+func expandInputs(inputs []string) ([]string, error) {
+	files := []string{}
+	seen := make(map[string]struct{})
+	add := func(file string) {
+		if _, ok := seen[file]; ok {
+			return
+		}
+		seen[file] = struct{}{}
+		files = append(files, file)
+	}
+	for _, input := range inputs {
+
+		// get the paths for this entry
+		paths := []string{input}
+		if strings.ContainsAny(input, "*?[") {
+			matches, err := filepath.Glob(input)
+			if err != nil {
+				return nil, fmt.Errorf("bad glob pattern (%v): %w", input, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %v", input)
+			}
+			paths = matches
+		}
+
+		// collect the files, searching any directories
+		found := 0
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil, fmt.Errorf("%v does not exist", path)
+				}
+				return nil, fmt.Errorf("can't access %v", path)
+			}
+			if !info.IsDir() {
+				add(path)
+				found++
+				continue
+			}
+			entries, err := ioutil.ReadDir(path)
+			if err != nil {
+				return nil, fmt.Errorf("can't read directory %v: %w", path, err)
+			}
+			for _, entry := range entries {
+				if entry.IsDir() || !isFASTQ(entry.Name()) {
+					continue
+				}
+				add(filepath.Join(path, entry.Name()))
+				found++
+			}
+		}
+		if found == 0 {
+			return nil, fmt.Errorf("no FASTQ files found in %v", input)
+		}
+	}
+	return files, nil
+}
+
+// fastqReader wraps an input FASTQ file and any
+// decompressor so that both are closed together.
+type fastqReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close implements the io.Closer interface.
+func (fr *fastqReader) Close() error {
+	var err error
+	for i := len(fr.closers) - 1; i >= 0; i-- {
+		if closeErr := fr.closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// openFASTQ will open a FASTQ file for reading. Gzip
+// and bgzip compressed files are detected from their
+// magic number and decompressed, regardless of the
+// file extension.
+func openFASTQ(path string) (io.ReadCloser, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(fh)
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		fh.Close()
+		return nil, err
+	}
+
+	// bgzip files are a series of gzip members, which the gzip reader handles
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			fh.Close()
+			return nil, fmt.Errorf("could not decompress %v: %w", path, err)
+		}
+		return &fastqReader{Reader: gr, closers: []io.Closer{fh, gr}}, nil
+	}
+	return &fastqReader{Reader: br, closers: []io.Closer{fh}}, nil
+}
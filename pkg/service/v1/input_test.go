@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// writeGzip will write data to a file as a
+// series of gzip members, as bgzip does.
+func writeGzip(t *testing.T, path string, members ...[]byte) {
+	var buf bytes.Buffer
+	for _, member := range members {
+		gw := gzip.NewWriter(&buf)
+		if _, err := gw.Write(member); err != nil {
+			t.Fatal(err)
+		}
+		if err := gw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestExpandInputs
+func TestExpandInputs(t *testing.T) {
+	inputDir := "./tmp-inputs"
+	defer os.RemoveAll(inputDir)
+	barcode := filepath.Join(inputDir, "barcode05")
+	if err := os.MkdirAll(filepath.Join(barcode, "nested"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(inputDir, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"b.fastq.gz", "a.fq", "c.FASTQ", "notes.txt", "nested/d.fastq"} {
+		if err := ioutil.WriteFile(filepath.Join(barcode, file), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, b, c := filepath.Join(barcode, "a.fq"), filepath.Join(barcode, "b.fastq.gz"), filepath.Join(barcode, "c.FASTQ")
+	tests := []struct {
+		inputs []string
+		files  []string
+	}{
+		{[]string{b}, []string{b}},
+		{[]string{barcode}, []string{a, b, c}},
+		{[]string{filepath.Join(barcode, "*.gz"), barcode}, []string{b, a, c}},
+		{[]string{filepath.Join(inputDir, "barcode*")}, []string{a, b, c}},
+		{[]string{filepath.Join(barcode, "notes.txt")}, []string{filepath.Join(barcode, "notes.txt")}},
+	}
+	for i, test := range tests {
+		files, err := expandInputs(test.inputs)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if len(files) != len(test.files) {
+			t.Fatalf("test %d: wanted files %v, got %v", i, test.files, files)
+		}
+		for j := range files {
+			if files[j] != test.files[j] {
+				t.Fatalf("test %d: wanted files %v, got %v", i, test.files, files)
+			}
+		}
+	}
+	for i, inputs := range [][]string{{filepath.Join(inputDir, "missing")}, {filepath.Join(inputDir, "empty")}, {filepath.Join(inputDir, "*.fastq")}, {"["}} {
+		if _, err := expandInputs(inputs); err == nil {
+			t.Fatalf("test %d: bad inputs were expanded", i)
+		}
+	}
+}
+
+// TestOpenFASTQ will check that plain, gzip and
+// bgzip files are read the same.
+func TestOpenFASTQ(t *testing.T) {
+	inputDir := "./tmp-open"
+	defer os.RemoveAll(inputDir)
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	read1, read2 := []byte("@read0\nACGT\n+\nIIII\n"), []byte("@read1\nTTTT\n+\nIIII\n")
+	want := append(append([]byte{}, read1...), read2...)
+	plain, gz, bgz := filepath.Join(inputDir, "reads.fastq"), filepath.Join(inputDir, "reads.fastq.gz"), filepath.Join(inputDir, "reads.bgz")
+	if err := ioutil.WriteFile(plain, want, 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeGzip(t, gz, want)
+	writeGzip(t, bgz, read1, read2)
+	for _, file := range []string{plain, gz, bgz} {
+		fh, err := openFASTQ(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadAll(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := fh.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("%v: wanted %q, got %q", file, want, got)
+		}
+	}
+}
+
+// TestProcessDirectory will check that a directory
+// of compressed FASTQ files can be processed.
+func TestProcessDirectory(t *testing.T) {
+	inputDb := "./tmp-directory"
+	defer os.RemoveAll(inputDb)
+	a, shutdown := newTestArcher(t, inputDb)
+	defer shutdown()
+
+	// write a barcode directory with a plain and a compressed FASTQ
+	ref := getTestReference(t)
+	barcode := filepath.Join(inputDb, "fastq_pass", "barcode05")
+	if err := os.MkdirAll(barcode, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFASTQ(t, filepath.Join(barcode, "reads_0.fastq"), []testRead{{ref[30:424], 'I'}})
+	writeTestFASTQ(t, filepath.Join(inputDb, "reads.fastq"), []testRead{{ref[350:774], 'I'}, {ref[700:1124], 'I'}})
+	data, err := ioutil.ReadFile(filepath.Join(inputDb, "reads.fastq"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeGzip(t, filepath.Join(barcode, "reads_1.fastq.gz"), data)
+
+	// process the directory
+	request := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "barcode05",
+		InputFASTQfiles: []string{barcode + "/"},
+		Scheme:          "test",
+		SchemeVersion:   1,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	sample := waitForSample(t, a, "barcode05")
+	if sample.GetState() != api.State_SUCCESS {
+		t.Fatalf("sample did not process: %v", sample.GetErrors())
+	}
+	if sample.GetFilesDiscovered() != 2 || len(sample.GetProcessRequest().GetInputFASTQfiles()) != 2 {
+		t.Fatalf("wanted 2 files discovered, got %d: %v", sample.GetFilesDiscovered(), sample.GetProcessRequest().GetInputFASTQfiles())
+	}
+	stats := sample.GetProcessStats()
+	if stats.GetTotalReads() != 3 || stats.GetKeptReads() != 3 {
+		t.Fatalf("wanted 3 of 3 reads kept, got %d of %d", stats.GetKeptReads(), stats.GetTotalReads())
+	}
+}
+
+// TestProcessTruncated will check that a truncated gzip
+// file fails the sample rather than uploading the reads
+// that could be read before the end of the file.
+func TestProcessTruncated(t *testing.T) {
+	for _, gated := range []bool{false, true} {
+		truncatedDb := fmt.Sprintf("./tmp-truncated-%v", gated)
+		defer os.RemoveAll(truncatedDb)
+		options := []ArcherOption{}
+		if gated {
+			options = append(options, SetQCGate(1, 0, 0, "block"))
+		}
+		a, shutdown := newTestArcher(t, truncatedDb, options...)
+
+		// write a compressed FASTQ and cut it off halfway
+		ref := getTestReference(t)
+		reads := make([]testRead, 2000)
+		for i := range reads {
+			reads[i] = testRead{ref[350:774], 'I'}
+		}
+		fastqFile := filepath.Join(truncatedDb, "reads.fastq")
+		writeTestFASTQ(t, fastqFile, reads)
+		data, err := ioutil.ReadFile(fastqFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeGzip(t, fastqFile+".gz", data)
+		compressed, err := ioutil.ReadFile(fastqFile + ".gz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fastqFile+".gz", compressed[:len(compressed)/2], 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// the sample should error and nothing should be uploaded
+		request := &api.ProcessRequest{
+			ApiVersion:      apiVersion,
+			SampleID:        "truncated",
+			InputFASTQfiles: []string{fastqFile + ".gz"},
+			Scheme:          "test",
+			SchemeVersion:   1,
+		}
+		if _, err := a.Process(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		sample := waitForSample(t, a, "truncated")
+		if sample.GetState() != api.State_ERROR || len(sample.GetErrors()) != 1 || !strings.Contains(sample.GetErrors()[0], "could not read") {
+			t.Fatalf("truncated file did not fail the sample (gated: %v): %v %v", gated, sample.GetState(), sample.GetErrors())
+		}
+		if sample.GetEndpoint() != "" {
+			t.Fatalf("truncated file was uploaded (gated: %v): %v", gated, sample.GetEndpoint())
+		}
+		if _, err := os.Stat(filepath.Join(truncatedDb, "uploads", "truncated.fastq.gz")); !os.IsNotExist(err) {
+			t.Fatalf("truncated file was uploaded (gated: %v)", gated)
+		}
+		if err := shutdown(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
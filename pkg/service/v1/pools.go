@@ -0,0 +1,69 @@
+package service
+
+import (
+	"github.com/will-rowe/archer/pkg/amplicons"
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// setPoolStats will summarise the amplicon coverage
+// for each primer pool of a sample. The relative
+// coverage of each pool is its mean coverage as a
+// fraction of the best covered pool.
+func setPoolStats(stats *api.SampleStats, as *amplicons.AmpliconSet) {
+	poolStats := make(map[string]*api.PoolStats)
+	for _, pool := range as.GetPoolNames() {
+		poolStats[pool] = &api.PoolStats{}
+	}
+	for _, amplicon := range as.GetAmpliconNames() {
+		ps := poolStats[as.GetPool(amplicon)]
+		ps.Amplicons++
+		if stats.GetAmpliconCoverage()[amplicon] != 0 {
+			ps.CoveredAmplicons++
+		}
+		ps.Reads += stats.GetAmpliconCoverage()[amplicon]
+		ps.NormalisedReads += stats.GetNormalisedCoverage()[amplicon]
+	}
+
+	// get the mean coverage, then the coverage relative to the best pool
+	var bestCoverage float32
+	for _, ps := range poolStats {
+		ps.MeanCoverage = float32(ps.GetReads()) / float32(ps.GetAmplicons())
+		if ps.GetMeanCoverage() > bestCoverage {
+			bestCoverage = ps.GetMeanCoverage()
+		}
+	}
+	if bestCoverage > 0 {
+		for _, ps := range poolStats {
+			ps.RelativeCoverage = ps.GetMeanCoverage() / bestCoverage
+		}
+	}
+	stats.PoolStats = poolStats
+}
+
+// setReferenceStats will summarise the amplicon coverage
+// for each reference sequence of a sample. The reference
+// coverage is estimated from the amplicons with at least
+// one kept read.
+func setReferenceStats(stats *api.SampleStats, as *amplicons.AmpliconSet) {
+	referenceStats := make(map[string]*api.ReferenceStats)
+	for _, refName := range as.GetReferenceNames() {
+		referenceStats[refName] = &api.ReferenceStats{
+			Length: int32(as.GetSequenceLength(refName)),
+		}
+	}
+	covered := []string{}
+	for _, amplicon := range as.GetAmpliconNames() {
+		rs := referenceStats[as.GetReference(amplicon)]
+		rs.Amplicons++
+		if stats.GetNormalisedCoverage()[amplicon] != 0 {
+			rs.CoveredAmplicons++
+			covered = append(covered, amplicon)
+		}
+		rs.Reads += stats.GetAmpliconCoverage()[amplicon]
+		rs.NormalisedReads += stats.GetNormalisedCoverage()[amplicon]
+	}
+	for refName, coverage := range as.GetCoverageByReference(covered) {
+		referenceStats[refName].ReferenceCoverage = float32(100 * coverage)
+	}
+	stats.ReferenceStats = referenceStats
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// TestProcessPoolStats will check the coverage
+// summary for each primer pool.
+func TestProcessPoolStats(t *testing.T) {
+	poolDb := "./tmp-pools"
+	defer os.RemoveAll(poolDb)
+	a, shutdown := newTestArcher(t, poolDb)
+	defer shutdown()
+
+	// amplicons 1 and 3 are in pool 1 and amplicon 2 is in pool 2,
+	// so write 4 reads for amplicon 1 and 1 read for amplicon 2
+	ref := getTestReference(t)
+	reads := []testRead{}
+	for i := 0; i < 4; i++ {
+		reads = append(reads, testRead{ref[30:424], 'I'})
+	}
+	reads = append(reads, testRead{ref[350:774], 'I'})
+	fastq := filepath.Join(poolDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, reads)
+	request := &api.ProcessRequest{
+		ApiVersion:       apiVersion,
+		SampleID:         "pools",
+		InputFASTQfiles:  []string{fastq},
+		Scheme:           "test",
+		SchemeVersion:    1,
+		MaxAmpliconDepth: 2,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	sample := waitForSample(t, a, "pools")
+	if sample.GetState() != api.State_SUCCESS {
+		t.Fatalf("sample did not process: %v", sample.GetErrors())
+	}
+	pools := sample.GetProcessStats().GetPoolStats()
+	if len(pools) != 2 {
+		t.Fatalf("wanted 2 primer pools, got %v", pools)
+	}
+	tests := []struct {
+		pool                                  string
+		amplicons, covered, reads, normalised int32
+		meanCoverage, relativeCoverage        float32
+	}{
+		{"1", 2, 1, 4, 2, 2, 1},
+		{"2", 1, 1, 1, 1, 1, 0.5},
+	}
+	for i, test := range tests {
+		ps := pools[test.pool]
+		if ps.GetAmplicons() != test.amplicons || ps.GetCoveredAmplicons() != test.covered || ps.GetReads() != test.reads || ps.GetNormalisedReads() != test.normalised {
+			t.Fatalf("test %d: incorrect counts for pool %v: %v", i, test.pool, ps)
+		}
+		if ps.GetMeanCoverage() != test.meanCoverage || ps.GetRelativeCoverage() != test.relativeCoverage {
+			t.Fatalf("test %d: incorrect coverage for pool %v: %v", i, test.pool, ps)
+		}
+	}
+}
+
+// TestProcessReferenceStats will check the coverage
+// summary for each reference of a segmented scheme.
+func TestProcessReferenceStats(t *testing.T) {
+	refDb := "./tmp-references"
+	defer os.RemoveAll(refDb)
+	a, shutdown := newTestArcher(t, refDb)
+	defer shutdown()
+
+	// the segments are the first and last 600 bases of the test reference,
+	// and each has amplicons 1 and 2, so write reads for seg1:1 and seg2:1
+	ref := getTestReference(t)
+	fastq := filepath.Join(refDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[30:324], 'I'}, {ref[30:324], 'I'}, {ref[620:914], 'I'}})
+	request := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "segments",
+		InputFASTQfiles: []string{fastq},
+		Scheme:          "test-segmented",
+		SchemeVersion:   1,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	sample := waitForSample(t, a, "segments")
+	if sample.GetState() != api.State_SUCCESS {
+		t.Fatalf("sample did not process: %v", sample.GetErrors())
+	}
+	stats := sample.GetProcessStats()
+	if len(stats.GetAmpliconCoverage()) != 4 || stats.GetAmpliconCoverage()["seg1:1"] != 2 || stats.GetAmpliconCoverage()["seg2:1"] != 1 {
+		t.Fatalf("reads were not assigned to the right segments: %v", stats.GetAmpliconCoverage())
+	}
+	references := stats.GetReferenceStats()
+	if len(references) != 2 {
+		t.Fatalf("wanted 2 references, got %v", references)
+	}
+	tests := []struct {
+		refName                    string
+		covered, reads, normalised int32
+		referenceCoverage          float32
+	}{
+		{"seg1", 1, 2, 2, 49},
+		{"seg2", 1, 1, 1, 49},
+	}
+	for i, test := range tests {
+		rs := references[test.refName]
+		if rs.GetAmplicons() != 2 || rs.GetCoveredAmplicons() != test.covered || rs.GetReads() != test.reads || rs.GetNormalisedReads() != test.normalised || rs.GetLength() != 600 {
+			t.Fatalf("test %d: incorrect counts for %v: %v", i, test.refName, rs)
+		}
+		if rs.GetReferenceCoverage() != test.referenceCoverage {
+			t.Fatalf("test %d: wanted %v coverage of %.1f%%, got %.1f%%", i, test.refName, test.referenceCoverage, rs.GetReferenceCoverage())
+		}
+	}
+}
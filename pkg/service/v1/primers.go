@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/grailbio/bio/encoding/fastq"
+
+	"github.com/will-rowe/archer/pkg/amplicons"
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// primerTrimModes maps the launch option names
+// to the primer trimming modes.
+var primerTrimModes = map[string]api.PrimerTrim{
+	"none": api.PrimerTrim_NO_TRIM,
+	"soft": api.PrimerTrim_SOFT_TRIM,
+	"hard": api.PrimerTrim_HARD_TRIM,
+}
+
+// parsePrimerTrim will return the PrimerTrim
+// mode for a mode name (none, soft or hard).
+func parsePrimerTrim(mode string) (api.PrimerTrim, error) {
+	m, ok := primerTrimModes[mode]
+	if !ok {
+		return api.PrimerTrim_DEFAULT_TRIM, fmt.Errorf("unknown primer trim mode: %v (must be none, soft or hard)", mode)
+	}
+	return m, nil
+}
+
+// getPrimerTrim returns the primer trimming mode
+// for a request, using the server setting if the
+// request does not set one.
+func (a *Archer) getPrimerTrim(request *api.ProcessRequest) api.PrimerTrim {
+	if request.GetPrimerTrim() != api.PrimerTrim_DEFAULT_TRIM {
+		return request.GetPrimerTrim()
+	}
+	return a.filters.primerTrim
+}
+
+// trimPrimers will trim the primers of an amplicon from a
+// read that has been assigned to it, using the trimming
+// mode in the sample stats, and update the primer stats.
+//
+// Soft trimming masks the primer bases in lower case and
+// sets their quality to 0, hard trimming removes them.
+// The read is left untrimmed if an error is returned.
+func trimPrimers(read *fastq.Read, as *amplicons.AmpliconSet, amplicon string, stats *api.SampleStats) error {
+	if stats.GetPrimerTrim() != api.PrimerTrim_SOFT_TRIM && stats.GetPrimerTrim() != api.PrimerTrim_HARD_TRIM {
+		return nil
+	}
+	sites, err := as.FindPrimers(amplicon, []byte(read.Seq))
+	if err != nil {
+		return err
+	}
+	if !sites.StartFound && !sites.EndFound {
+		return nil
+	}
+
+	// update the stats
+	trimStats := stats.PrimerTrimStats
+	atomic.AddInt32(&trimStats.TrimmedReads, 1)
+	if sites.StartFound {
+		atomic.AddInt32(&trimStats.StartPrimers, 1)
+	}
+	if sites.EndFound {
+		atomic.AddInt32(&trimStats.EndPrimers, 1)
+	}
+	atomic.AddInt64(&trimStats.TrimmedBases, int64(len(read.Seq)-(sites.InsertEnd-sites.InsertStart)))
+
+	// trim the read
+	if stats.GetPrimerTrim() == api.PrimerTrim_HARD_TRIM {
+		read.Seq = read.Seq[sites.InsertStart:sites.InsertEnd]
+		read.Qual = read.Qual[sites.InsertStart:sites.InsertEnd]
+		return nil
+	}
+	read.Seq = strings.ToLower(read.Seq[:sites.InsertStart]) + read.Seq[sites.InsertStart:sites.InsertEnd] + strings.ToLower(read.Seq[sites.InsertEnd:])
+	read.Qual = strings.Repeat("!", sites.InsertStart) + read.Qual[sites.InsertStart:sites.InsertEnd] + strings.Repeat("!", len(read.Qual)-sites.InsertEnd)
+	return nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grailbio/bio/encoding/fastq"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// readUpload will read the uploaded reads for a
+// sample from the test storage.
+func readUpload(t *testing.T, dbPath, sampleID string) []fastq.Read {
+	fh, err := openFASTQ(filepath.Join(dbPath, "uploads", sampleID+".fastq.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	reads := []fastq.Read{}
+	var read fastq.Read
+	scanner := fastq.NewScanner(fh, fastq.All)
+	for scanner.Scan(&read) {
+		reads = append(reads, read)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return reads
+}
+
+// TestProcessPrimerTrim will check that primers are
+// trimmed from kept reads for each trimming mode.
+func TestProcessPrimerTrim(t *testing.T) {
+	trimDb := "./tmp-primers"
+	defer os.RemoveAll(trimDb)
+	a, shutdown := newTestArcher(t, trimDb, SetPrimerTrim("hard"))
+	defer shutdown()
+	if _, err := parsePrimerTrim("both"); err == nil {
+		t.Fatal("expected unknown primer trim mode to fail")
+	}
+
+	// write a forward read for amplicon 1 and a reverse read for amplicon 2
+	ref := getTestReference(t)
+	rc := strings.NewReplacer("A", "t", "C", "g", "G", "c", "T", "a")
+	rev := []byte(strings.ToUpper(rc.Replace(ref[350:774])))
+	for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+		rev[i], rev[j] = rev[j], rev[i]
+	}
+	reads := filepath.Join(trimDb, "reads.fastq")
+	writeTestFASTQ(t, reads, []testRead{{ref[30:424], 'I'}, {string(rev), 'I'}})
+
+	// primers are 24 bases, so trimming leaves the amplicon inserts
+	tests := []struct {
+		mode    api.PrimerTrim
+		lengths []int
+	}{
+		{api.PrimerTrim_DEFAULT_TRIM, []int{346, 376}},
+		{api.PrimerTrim_SOFT_TRIM, []int{394, 424}},
+		{api.PrimerTrim_NO_TRIM, []int{394, 424}},
+	}
+	for i, test := range tests {
+		request := &api.ProcessRequest{
+			ApiVersion:      apiVersion,
+			SampleID:        test.mode.String(),
+			InputFASTQfiles: []string{reads},
+			Scheme:          "test",
+			SchemeVersion:   1,
+			PrimerTrim:      test.mode,
+		}
+		if _, err := a.Process(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		sample := waitForSample(t, a, test.mode.String())
+		if sample.GetState() != api.State_SUCCESS {
+			t.Fatalf("test %d: sample did not process: %v", i, sample.GetErrors())
+		}
+		uploaded := readUpload(t, trimDb, test.mode.String())
+		if len(uploaded) != 2 || len(uploaded[0].Seq) != test.lengths[0] || len(uploaded[1].Seq) != test.lengths[1] {
+			t.Fatalf("test %d: wanted read lengths %v, got %d reads", i, test.lengths, len(uploaded))
+		}
+
+		// check the stats
+		stats := sample.GetProcessStats().GetPrimerTrimStats()
+		switch test.mode {
+		case api.PrimerTrim_NO_TRIM:
+			if stats.GetTrimmedReads() != 0 || sample.GetProcessStats().GetPrimerTrim() != api.PrimerTrim_NO_TRIM {
+				t.Fatalf("test %d: reads were trimmed: %v", i, stats)
+			}
+		default:
+			if stats.GetTrimmedReads() != 2 || stats.GetStartPrimers() != 2 || stats.GetEndPrimers() != 2 || stats.GetTrimmedBases() != 96 {
+				t.Fatalf("test %d: unexpected primer trim stats: %v", i, stats)
+			}
+		}
+
+		// soft trimming should mask the primers
+		if test.mode == api.PrimerTrim_SOFT_TRIM {
+			read := uploaded[0]
+			if read.Seq[:24] != strings.ToLower(ref[30:54]) || read.Seq[24:370] != ref[54:400] || read.Qual[:24] != strings.Repeat("!", 24) || read.Qual[24] != 'I' {
+				t.Fatalf("test %d: primers were not masked: %v", i, read.Seq)
+			}
+		}
+	}
+
+	// a read that can't be trimmed should be left as it is
+	as, err := a.getAmpliconSet("test", 1, a.sketchParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := fastq.Read{ID: "@read", Seq: ref[30:424], Unk: "+", Qual: strings.Repeat("I", 394)}
+	stats := &api.SampleStats{PrimerTrim: api.PrimerTrim_SOFT_TRIM, PrimerTrimStats: &api.PrimerTrimStats{}}
+	if err := trimPrimers(&read, as, "missing", stats); err == nil {
+		t.Fatal("expected unknown amplicon to fail primer trimming")
+	}
+	if read.Seq != ref[30:424] || stats.GetPrimerTrimStats().GetTrimmedReads() != 0 {
+		t.Fatal("read was changed by a failed primer trim")
+	}
+}
@@ -5,14 +5,15 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
+	"sync/atomic"
 
-	"github.com/golang/protobuf/ptypes"
 	"github.com/grailbio/bio/encoding/fastq"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/will-rowe/archer/pkg/amplicons"
 	api "github.com/will-rowe/archer/pkg/api/v1"
 )
 
@@ -56,9 +57,47 @@ func (a *Archer) Process(ctx context.Context, request *api.ProcessRequest) (*api
 		return nil, err
 	}
 
-	// add the sample to the processing queue
+	// take a copy of the sample for the queued event, as once the
+	// sample is registered it can be changed by a cancel request
+	queued := proto.Clone(sampleInfo).(*api.SampleInfo)
+
+	// register the sample so that it can be cancelled, unless the service is shutting down
+	ctx, cancel := context.WithCancel(context.Background())
+	a.Lock()
+	select {
+	case <-a.quit:
+		a.Unlock()
+		cancel()
+		return nil, a.abandonSample(sampleInfo)
+	default:
+	}
+	a.inFlight[sampleInfo.GetSampleID()] = &inFlightSample{
+		sample: sampleInfo,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	a.queueing.Add(1)
+	a.Unlock()
+	defer a.queueing.Done()
+
+	// add the sample to the processing queue, giving up if the service shuts down first
+	a.publishEvent(api.EventType_QUEUED, queued, api.State_UNKNOWN, nil)
+	select {
+	case a.processChan <- sampleInfo:
+	case <-a.quit:
+		a.Lock()
+		job, ok := a.inFlight[sampleInfo.GetSampleID()]
+		delete(a.inFlight, sampleInfo.GetSampleID())
+		a.Unlock()
+		cancel()
+
+		// the sample may have been cancelled whilst it was waiting
+		if !ok {
+			return nil, status.Errorf(codes.Unavailable, "service shut down before the sample was queued (%s)", sampleInfo.GetSampleID())
+		}
+		return nil, a.abandonSample(job.sample)
+	}
 	log.Infof("process response sent and sample added to queue for %v", request.GetSampleID())
-	a.processChan <- sampleInfo
 
 	// create a response and return
 	return &api.ProcessResponse{
@@ -67,110 +106,315 @@ func (a *Archer) Process(ctx context.Context, request *api.ProcessRequest) (*api
 	}, nil
 }
 
+// abandonSample will mark a sample as errored when
+// the service shuts down before it could be queued,
+// returning the error for the process request.
+func (a *Archer) abandonSample(sample *api.SampleInfo) error {
+	err := fmt.Errorf("service shut down before the sample was queued (%s)", sample.GetSampleID())
+	checkError(sample, err)
+	if dbErr := a.finishSample(sample, api.State_UNKNOWN); dbErr != nil {
+		log.Errorf("could not update db for %v: %v", sample.GetSampleID(), dbErr)
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
 // processWorker handles the actual work for Archer.
 // This includes fastq checking, filtering, upload etc.
 func (a *Archer) processWorker() {
-	var read fastq.Read
 
 	// collect requests
 	for sample := range a.processChan {
+
+		// get the sample context and skip the sample if it was cancelled whilst queued
+		a.Lock()
+		job, ok := a.inFlight[sample.GetSampleID()]
+		if !ok || job.ctx.Err() != nil {
+			a.Unlock()
+			log.Infof("skipping cancelled sample %v", sample.GetSampleID())
+			continue
+		}
+		ctx := job.ctx
+		job.running = true
+		sample.State = api.State_RUNNING
+		a.Unlock()
 		log.Infof("worker started for %v", sample.GetSampleID())
 
-		// make a copy of the amplicon set for this request
-		as := a.ampliconCache[generateAmpliconSetID(sample.GetProcessRequest().GetScheme(), sample.GetProcessRequest().GetSchemeVersion())]
+		// get the amplicon set for this request
+		as, err := a.getAmpliconSet(sample.GetProcessRequest().GetScheme(), sample.GetProcessRequest().GetSchemeVersion(), a.getSketchParams(sample.GetProcessRequest()))
+		if checkError(sample, err) {
+			a.endSample(job, api.State_UNKNOWN)
+			continue
+		}
 
-		// update the sample state, get the outfile handler and stats holder ready
-		sample.State = api.State_RUNNING
+		// get the stats holder ready
 		sample.ProcessStats = &api.SampleStats{
-			TotalReads:       0,
-			KeptReads:        0,
-			AmpliconCoverage: make(map[string]int32),
-			MeanAmpliconSize: int32(as.GetMeanSize()),
+			TotalReads:         0,
+			KeptReads:          0,
+			AmpliconCoverage:   make(map[string]int32),
+			NormalisedCoverage: make(map[string]int32),
+			MeanAmpliconSize:   int32(as.GetMeanSize()),
+		}
+		a.setFilters(sample)
+		sample.SampleQC = nil
+		sample.ProcessStats.KmerSize = int32(as.GetSketchParams().KmerSize)
+		sample.ProcessStats.SketchSize = int32(as.GetSketchParams().SketchSize)
+		sample.ProcessStats.Canonical = as.GetSketchParams().Canonical
+
+		// record the state change and start reporting progress
+		if err := a.addSample(sample); err != nil {
+			log.Errorf("could not update db for %v: %v", sample.GetSampleID(), err)
+		}
+		a.publishEvent(api.EventType_STATE_CHANGE, sample, api.State_UNKNOWN, nil)
+		tracker := &progressTracker{}
+		stopProgress := make(chan struct{})
+		go a.reportProgress(sample, tracker, stopProgress)
+
+		// process the sample, retrying the whole sample if the upload hits a transient
+		// error (staged samples only retry the upload, see stageSample)
+		var endpoint string
+		process := func() (string, error) {
+			return a.processSample(ctx, sample, as, tracker)
+		}
+		if a.qcGate.enabled() {
+			endpoint, err = process()
+		} else {
+			endpoint, err = a.retryUpload(ctx, sample, process)
+		}
+		close(stopProgress)
+		cancelled := err != nil && ctx.Err() != nil
+		if err != nil && !cancelled {
+			if sample.GetRetries() != 0 {
+				err = fmt.Errorf("upload failed after %d retries: %w", sample.GetRetries(), err)
+			}
+			checkError(sample, err)
+		}
+		sample.Endpoint = endpoint
+
+		// update status
+		finalState := api.State_SUCCESS
+		switch {
+		case cancelled:
+			finalState = api.State_CANCELLED
+		case len(sample.GetErrors()) != 0:
+			finalState = api.State_ERROR
+		case len(sample.GetSampleQC().GetGateFailures()) != 0:
+			finalState = api.State_FAILED_QC
 		}
-		for amplicon := range *as {
-			sample.ProcessStats.AmpliconCoverage[amplicon] = 0
+
+		// only keep the QC report if the sample was fully processed
+		if finalState == api.State_CANCELLED || finalState == api.State_ERROR {
+			sample.SampleQC = nil
 		}
-		lengthRange := int32(lengthThreshold * float64(sample.ProcessStats.MeanAmpliconSize))
-		sample.ProcessStats.LengthMax = sample.ProcessStats.MeanAmpliconSize + lengthRange
-		sample.ProcessStats.LengthMin = sample.ProcessStats.MeanAmpliconSize - lengthRange
-
-		// open a gz writer for the output reads and a reader for AWS upload
-		reader, writer := io.Pipe()
-		readChan := make(chan fastq.Read)
-		go func() {
-			gw := gzip.NewWriter(writer)
-			fw := fastq.NewWriter(gw)
-			for read := range readChan {
-				fw.Write(&read)
+		sample.State = finalState
+		a.endSample(job, api.State_RUNNING)
+		log.Infof("worker finished for %v", sample.GetSampleID())
+	}
+}
+
+// endSample will remove a sample from the in-flight
+// samples, write it back to the db and let any
+// watchers know.
+func (a *Archer) endSample(job *inFlightSample, previousState api.State) {
+	a.Lock()
+	job.cancel()
+	delete(a.inFlight, job.sample.GetSampleID())
+	a.Unlock()
+	if err := a.finishSample(job.sample, previousState); err != nil {
+		log.Errorf("could not update db for %v: %v", job.sample.GetSampleID(), err)
+	}
+}
+
+// processSample will make a single attempt at filtering
+// the reads for a sample and uploading the kept reads.
+// It returns the upload location and any upload error.
+func (a *Archer) processSample(ctx context.Context, sample *api.SampleInfo, as *amplicons.AmpliconSet, tracker *progressTracker) (string, error) {
+
+	// reset the stats from any previous attempt
+	atomic.StoreInt32(&sample.ProcessStats.TotalReads, 0)
+	atomic.StoreInt32(&sample.ProcessStats.KeptReads, 0)
+	resetFilterStats(sample.ProcessStats)
+	atomic.StoreInt32(&tracker.currentFile, 0)
+	atomic.StoreInt64(&tracker.bytesUploaded, 0)
+	for _, amplicon := range as.GetAmpliconNames() {
+		sample.ProcessStats.AmpliconCoverage[amplicon] = 0
+		sample.ProcessStats.NormalisedCoverage[amplicon] = 0
+	}
+
+	// get the read header scrubber, which keeps any header mapping locally
+	sample.ProcessStats.HeaderMap = a.getHeaderMapPath(sample)
+	scrubber, err := newHeaderScrubber(sample.ProcessStats.GetHeaderPolicy(), sample.GetSampleID(), sample.ProcessStats.GetHeaderMap())
+	if err != nil {
+		return "", err
+	}
+
+	// open each FASTQ file for the sample, stopping at any unreadable
+	// file (e.g. a truncated gzip) so that the reads aren't uploaded
+	readChan := make(chan fastq.Read)
+	filterDone := make(chan struct{})
+	var readErr error
+	go func() {
+		defer close(filterDone)
+		var read fastq.Read
+		stats := sample.ProcessStats
+		trimFailed := false
+		for i, file := range sample.GetProcessRequest().GetInputFASTQfiles() {
+			atomic.StoreInt32(&tracker.currentFile, int32(i))
+			fh, err := openFASTQ(file)
+			if checkError(sample, err) {
+				continue
 			}
-			gw.Close()
-			writer.Close()
-		}()
-
-		// open each FASTQ file for the sample
-		go func() {
-			for _, file := range sample.GetProcessRequest().GetInputFASTQfiles() {
-				fh, err := os.Open(file)
+			faScanner := fastq.NewScanner(fh, fastq.All)
+
+			// filter reads against amplicons
+			for faScanner.Scan(&read) {
+
+				// stop reading if the sample has been cancelled
+				if ctx.Err() != nil {
+					break
+				}
+				atomic.AddInt32(&stats.TotalReads, 1)
+				meanQuality := getMeanQuality(read.Qual)
+				atomic.AddInt32(&stats.QualityHistogram[getQualityBin(meanQuality)], 1)
+
+				// quality trim
+				if stats.GetTrimWindow() > 0 {
+					if keep := qualityTrim(read.Qual, int(stats.GetTrimWindow()), float64(stats.GetTrimQuality())); keep < len(read.Seq) {
+						read.Seq, read.Qual = read.Seq[:keep], read.Qual[:keep]
+						meanQuality = getMeanQuality(read.Qual)
+						atomic.AddInt32(&stats.TrimmedReads, 1)
+					}
+				}
+
+				// length filter, which also drops reads too short to sketch
+				if len(read.Seq) < int(stats.GetLengthMin()) || len(read.Seq) > int(stats.GetLengthMax()) || len(read.Seq) < int(stats.GetKmerSize()) {
+					atomic.AddInt32(&stats.DroppedReads.Length, 1)
+					continue
+				}
+
+				// quality filter
+				if stats.GetMinMeanQuality() > 0 && meanQuality < float64(stats.GetMinMeanQuality()) {
+					atomic.AddInt32(&stats.DroppedReads.Quality, 1)
+					continue
+				}
+
+				// filter against amplicons
+				hits, err := as.GetHits([]byte(read.Seq), stats.GetScoring(), 2)
 				if checkError(sample, err) {
 					continue
 				}
-				defer fh.Close()
-				faScanner := fastq.NewScanner(fh, fastq.All)
+				if len(hits) == 0 || hits[0].Score < float64(stats.GetScoreThreshold()) {
+					atomic.AddInt32(&stats.DroppedReads.NoHit, 1)
+					continue
+				}
+				topHit := hits[0].Amplicon
 
-				// filter reads against amplicons
-				for faScanner.Scan(&read) {
-					sample.ProcessStats.TotalReads++
+				// host screen
+				if a.hostIndex != nil && a.hostIndex.IsHost([]byte(read.Seq), float64(a.hostThreshold)) {
+					atomic.AddInt32(&stats.DroppedReads.Host, 1)
+					continue
+				}
 
-					// length filter
-					if len(read.Seq) < int(sample.ProcessStats.GetLengthMin()) || len(read.Seq) > int(sample.ProcessStats.GetLengthMax()) {
-						continue
-					}
+				// flag ambiguous and chimeric reads
+				if checkMultiHit(as, hits, stats) {
+					atomic.AddInt32(&stats.DroppedReads.MultiHit, 1)
+					continue
+				}
+				stats.AmpliconCoverage[topHit]++
 
-					// filter against amplicons
-					topHit, score, err := as.GetTopHit([]byte(read.Seq))
-					if checkError(sample, err) {
-						continue
-					}
-					if score < jaccardThreshold {
-						continue
-					}
-					sample.ProcessStats.AmpliconCoverage[topHit]++
+				// depth cap, keeping the first reads for each amplicon
+				if stats.GetMaxAmpliconDepth() > 0 && stats.NormalisedCoverage[topHit] >= stats.GetMaxAmpliconDepth() {
+					atomic.AddInt32(&stats.DroppedReads.DepthCap, 1)
+					continue
+				}
+				stats.NormalisedCoverage[topHit]++
 
-					// keep the read and send it to the uploader
-					sample.ProcessStats.KeptReads++
-					readChan <- read
+				// trim primers, keeping the read untrimmed if they can't be found (only warning once, as
+				// this is a problem with the scheme rather than the read)
+				if err := trimPrimers(&read, as, topHit, stats); err != nil && !trimFailed {
+					trimFailed = true
+					log.Warnf("could not trim primers for %v, keeping reads untrimmed: %v", sample.GetSampleID(), err)
 				}
+
+				// keep the read and send it to the uploader
+				atomic.AddInt32(&stats.KeptReads, 1)
+				atomic.AddInt32(&stats.KeptQualityHistogram[getQualityBin(meanQuality)], 1)
+				select {
+				case readChan <- read:
+				case <-ctx.Done():
+				}
+			}
+			fh.Close()
+			if ctx.Err() != nil {
+				break
+			}
+			if err := faScanner.Err(); err != nil {
+				readErr = fmt.Errorf("could not read %v: %w", file, err)
+				checkError(sample, readErr)
+				break
 			}
+		}
 
-			// signal end the AWS upload
-			close(readChan)
-		}()
+		// signal end the AWS upload
+		close(readChan)
+	}()
 
-		// start the uploader
-		endpoint, err := a.bucket.Upload(reader, fmt.Sprintf("%s.fastq.gz", sample.GetSampleID()))
-		if err != nil {
-			panic(err)
+	// stage the reads locally if the QC gate needs to check the sample before upload
+	key := fmt.Sprintf("%s.fastq.gz", sample.GetSampleID())
+	if a.qcGate.enabled() {
+		return a.stageSample(ctx, sample, as, tracker, readChan, filterDone, &readErr, scrubber, key)
+	}
+
+	// otherwise, open a gz writer for the output reads and a reader for AWS upload
+	reader, writer := io.Pipe()
+	go func() {
+		err := writeReads(ctx, readChan, writer, scrubber)
+		if err == nil {
+			err = readErr
 		}
-		sample.Endpoint = endpoint
+		writer.CloseWithError(err)
+	}()
 
-		// TODO: handle any errors
-		// (TODO: decide if upload continues if errors found)
+	// start the uploader, closing the pipe once it returns
+	// so that the writer doesn't block on a failed upload
+	endpoint, err := a.storage.Upload(ctx, &countingReader{reader: reader, count: &tracker.bytesUploaded}, key)
+	reader.CloseWithError(io.ErrClosedPipe)
+	<-filterDone
 
-		// update status
-		if len(sample.GetErrors()) == 0 {
-			sample.State = api.State_SUCCESS
-		}
-		sample.EndTime = ptypes.TimestampNow()
+	// the read error is already recorded, and has failed the upload
+	if readErr != nil {
+		return "", nil
+	}
+	a.summariseSample(sample, as)
+	return endpoint, err
+}
 
-		// write back to db
-		if err := a.addSample(sample); err != nil {
-			panic(err)
+// writeReads will write the kept reads for a sample as
+// gzipped FASTQ, scrubbing the read headers. The read
+// channel is drained after any error so that filtering
+// isn't blocked, and the first error is returned.
+func writeReads(ctx context.Context, readChan <-chan fastq.Read, w io.Writer, scrubber *headerScrubber) error {
+	gw := gzip.NewWriter(w)
+	fw := fastq.NewWriter(gw)
+	var err error
+	for read := range readChan {
+		if err != nil {
+			continue
 		}
-
-		// let a watcher know if needed
-		if a.watcherChan != nil {
-			a.watcherChan <- sample
+		if err = scrubber.scrub(&read); err != nil {
+			continue
 		}
-		log.Infof("worker finished for %v", sample.GetSampleID())
+		err = fw.Write(&read)
+	}
+	if closeErr := scrubber.close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+
+	// fail the output if the sample was cancelled, rather than sending a truncated file
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return err
 	}
+	return gw.Close()
 }
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// progressTracker records the progress of a running
+// sample that isn't held in the SampleStats.
+//
+// Fields are accessed atomically as they are updated
+// by the process worker whilst being reported.
+type progressTracker struct {
+	currentFile   int32
+	bytesUploaded int64
+}
+
+// countingReader wraps a reader and counts the
+// number of bytes read from it.
+type countingReader struct {
+	reader io.Reader
+	count  *int64
+}
+
+// Read implements the io.Reader interface.
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	atomic.AddInt64(cr.count, int64(n))
+	return n, err
+}
+
+// snapshot returns the current progress of a
+// running sample.
+func (pt *progressTracker) snapshot(sample *api.SampleInfo) *api.SampleProgress {
+	return &api.SampleProgress{
+		TotalReads:    atomic.LoadInt32(&sample.ProcessStats.TotalReads),
+		KeptReads:     atomic.LoadInt32(&sample.ProcessStats.KeptReads),
+		CurrentFile:   atomic.LoadInt32(&pt.currentFile),
+		BytesUploaded: atomic.LoadInt64(&pt.bytesUploaded),
+	}
+}
+
+// reportProgress will periodically send progress
+// events for a running sample until the stop
+// channel is closed.
+func (a *Archer) reportProgress(sample *api.SampleInfo, tracker *progressTracker, stop <-chan struct{}) {
+	ticker := time.NewTicker(a.progressInterval)
+	defer ticker.Stop()
+
+	// only send a summary of the sample as it is still being updated by the worker
+	summary := &api.SampleInfo{
+		SampleID:  sample.GetSampleID(),
+		State:     api.State_RUNNING,
+		StartTime: sample.GetStartTime(),
+	}
+	for {
+		select {
+		case <-ticker.C:
+			a.publishEvent(api.EventType_PROGRESS, summary, api.State_RUNNING, tracker.snapshot(sample))
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// TestReportProgress will check that progress events
+// are published for a running sample.
+func TestReportProgress(t *testing.T) {
+	a := &Archer{version: apiVersion, progressInterval: 10 * time.Millisecond, watchers: newWatcherHub()}
+	sub := a.watchers.subscribe(watcherBufferSize)
+	sample := &api.SampleInfo{SampleID: "s1", State: api.State_RUNNING, ProcessStats: &api.SampleStats{TotalReads: 10, KeptReads: 4}}
+	tracker := &progressTracker{currentFile: 1}
+
+	// count some uploaded bytes
+	cr := &countingReader{reader: bytes.NewReader(make([]byte, 100)), count: &tracker.bytesUploaded}
+	if _, err := ioutil.ReadAll(cr); err != nil {
+		t.Fatal(err)
+	}
+
+	// check the progress event
+	stop := make(chan struct{})
+	go a.reportProgress(sample, tracker, stop)
+	event := <-sub.updates
+	close(stop)
+	if event.GetEventType() != api.EventType_PROGRESS || event.GetSamples()[0].GetSampleID() != "s1" {
+		t.Fatalf("unexpected event: %v", event)
+	}
+	progress := event.GetProgress()
+	if progress.GetTotalReads() != 10 || progress.GetKeptReads() != 4 || progress.GetCurrentFile() != 1 || progress.GetBytesUploaded() != 100 {
+		t.Fatalf("unexpected progress: %v", progress)
+	}
+}
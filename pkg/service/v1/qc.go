@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/will-rowe/archer/pkg/amplicons"
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+const (
+
+	// DefaultQCMinDepth is the number of kept reads an amplicon needs to pass QC (the ARTIC pipeline masks below 20x)
+	DefaultQCMinDepth = 20
+
+	// DefaultQCWarnCoverage is the estimated reference coverage (percentage) below which a sample is given a WARN
+	DefaultQCWarnCoverage = 90.0
+
+	// DefaultQCFailCoverage is the estimated reference coverage (percentage) below which a sample is given a FAIL
+	DefaultQCFailCoverage = 50.0
+)
+
+// checkQCThresholds will check the reference coverage
+// thresholds used for the QC verdict.
+func checkQCThresholds(warnCoverage, failCoverage float64) error {
+	if warnCoverage < 0 || warnCoverage > 100 || failCoverage < 0 || failCoverage > 100 {
+		return fmt.Errorf("QC coverage thresholds must be between 0 and 100")
+	}
+	if failCoverage > warnCoverage {
+		return fmt.Errorf("QC fail coverage is greater than the warn coverage (%.1f > %.1f)", failCoverage, warnCoverage)
+	}
+	return nil
+}
+
+// getQCMinDepth returns the number of kept reads an
+// amplicon needs to pass QC, using the server setting
+// if the request does not set one.
+func (a *Archer) getQCMinDepth(request *api.ProcessRequest) int32 {
+	if request.GetQcMinDepth() != 0 {
+		return request.GetQcMinDepth()
+	}
+	return a.filters.qcMinDepth
+}
+
+// summariseSample will summarise the coverage of each
+// primer pool and reference, and report any amplicon
+// dropouts, once the reads for a sample have been
+// filtered.
+func (a *Archer) summariseSample(sample *api.SampleInfo, as *amplicons.AmpliconSet) {
+	setPoolStats(sample.ProcessStats, as)
+	setReferenceStats(sample.ProcessStats, as)
+	sample.SampleQC = a.getSampleQC(sample, as)
+}
+
+// getSampleQC returns the amplicon dropout report for a
+// processed sample. Amplicons pass if their kept reads
+// (the normalised coverage) reach the minimum depth and
+// the passing amplicons are used to estimate how much of
+// the reference is covered, which sets the verdict.
+func (a *Archer) getSampleQC(sample *api.SampleInfo, as *amplicons.AmpliconSet) *api.SampleQC {
+	qc := &api.SampleQC{
+		MinDepth:         a.getQCMinDepth(sample.GetProcessRequest()),
+		TotalAmplicons:   int32(as.GetNumAmplicons()),
+		DroppedAmplicons: []string{},
+		WarnCoverage:     a.filters.qcWarnCoverage,
+		FailCoverage:     a.filters.qcFailCoverage,
+	}
+	passed := []string{}
+	for _, amplicon := range as.GetAmpliconNames() {
+		if sample.GetProcessStats().GetNormalisedCoverage()[amplicon] < qc.GetMinDepth() {
+			qc.DroppedAmplicons = append(qc.DroppedAmplicons, amplicon)
+			continue
+		}
+		passed = append(passed, amplicon)
+	}
+	qc.PassedAmplicons = int32(len(passed))
+	qc.OffTarget = getOffTarget(sample.GetProcessStats())
+	qc.ReferenceCoverage = float32(100 * as.GetReferenceCoverage(passed))
+	switch {
+	case qc.GetReferenceCoverage() < qc.GetFailCoverage():
+		qc.Verdict = api.QCVerdict_FAIL
+	case qc.GetReferenceCoverage() < qc.GetWarnCoverage():
+		qc.Verdict = api.QCVerdict_WARN
+	default:
+		qc.Verdict = api.QCVerdict_PASS
+	}
+	return qc
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// TestProcessSampleQC will check the amplicon dropout
+// report and QC verdict for a processed sample.
+func TestProcessSampleQC(t *testing.T) {
+	qcDb := "./tmp-qc"
+	defer os.RemoveAll(qcDb)
+	a, shutdown := newTestArcher(t, qcDb, SetSampleQC(2, 90, 50))
+	defer shutdown()
+	for i, option := range []ArcherOption{SetSampleQC(0, 90, 50), SetSampleQC(2, 101, 50), SetSampleQC(2, 50, 90)} {
+		if err := option(a); err == nil {
+			t.Fatalf("test %d: bad QC setting was accepted", i)
+		}
+	}
+
+	// write 2 reads for amplicons 1 and 2, and 1 read for amplicon 3
+	// (amplicons 1 and 2 cover 744 bases of the 1200 base reference)
+	ref := getTestReference(t)
+	fastq := filepath.Join(qcDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[30:424], 'I'}, {ref[30:424], 'I'}, {ref[350:774], 'I'}, {ref[350:774], 'I'}, {ref[700:1124], 'I'}})
+	tests := []struct {
+		minDepth int32
+		verdict  api.QCVerdict
+		dropped  []string
+		coverage float64
+	}{
+		{0, api.QCVerdict_WARN, []string{"3"}, 62},
+		{1, api.QCVerdict_PASS, []string{}, 1094.0 / 12},
+		{3, api.QCVerdict_FAIL, []string{"1", "2", "3"}, 0},
+	}
+	for i, test := range tests {
+		sampleID := fmt.Sprintf("qc-%d", i)
+		request := &api.ProcessRequest{
+			ApiVersion:      apiVersion,
+			SampleID:        sampleID,
+			InputFASTQfiles: []string{fastq},
+			Scheme:          "test",
+			SchemeVersion:   1,
+			QcMinDepth:      test.minDepth,
+		}
+		if _, err := a.Process(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		sample := waitForSample(t, a, sampleID)
+		if sample.GetState() != api.State_SUCCESS {
+			t.Fatalf("test %d: sample did not process: %v", i, sample.GetErrors())
+		}
+		qc := sample.GetSampleQC()
+		if qc.GetVerdict() != test.verdict || qc.GetTotalAmplicons() != 3 || qc.GetPassedAmplicons() != int32(3-len(test.dropped)) {
+			t.Fatalf("test %d: wanted %v verdict, got %v", i, test.verdict, qc)
+		}
+		if fmt.Sprint(qc.GetDroppedAmplicons()) != fmt.Sprint(test.dropped) {
+			t.Fatalf("test %d: wanted dropped amplicons %v, got %v", i, test.dropped, qc.GetDroppedAmplicons())
+		}
+		if math.Abs(float64(qc.GetReferenceCoverage())-test.coverage) > 1e-3 {
+			t.Fatalf("test %d: wanted reference coverage %.2f, got %.2f", i, test.coverage, qc.GetReferenceCoverage())
+		}
+		if (test.minDepth == 0 && qc.GetMinDepth() != 2) || qc.GetWarnCoverage() != 90 || qc.GetFailCoverage() != 50 {
+			t.Fatalf("test %d: QC settings not recorded: %v", i, qc)
+		}
+	}
+	if err := checkFilters(&api.ProcessRequest{QcMinDepth: -1}); err == nil {
+		t.Fatal("negative QC depth passed the check")
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// RecoveryPolicy sets how Archer handles samples that
+// were queued or running when the service last stopped.
+//
+// Uploads are streamed whilst reads are filtered, so a
+// partially processed sample can't be continued. Both
+// resumed and restarted samples are therefore processed
+// again from their first FASTQ file.
+type RecoveryPolicy int
+
+const (
+	// RecoverResume will re-queue interrupted samples, keeping their original start time
+	RecoverResume RecoveryPolicy = iota
+
+	// RecoverRestart will re-queue interrupted samples as if they had just been submitted
+	RecoverRestart
+
+	// RecoverError will mark interrupted samples as errored
+	RecoverError
+)
+
+// recoveryPolicies links the policy names to the policies
+var recoveryPolicies = map[string]RecoveryPolicy{
+	"resume":  RecoverResume,
+	"restart": RecoverRestart,
+	"error":   RecoverError,
+}
+
+// ParseRecoveryPolicy will return the RecoveryPolicy
+// for a policy name (resume, restart or error).
+func ParseRecoveryPolicy(policy string) (RecoveryPolicy, error) {
+	p, ok := recoveryPolicies[policy]
+	if !ok {
+		return 0, fmt.Errorf("unknown recovery policy: %v (must be resume, restart or error)", policy)
+	}
+	return p, nil
+}
+
+// String returns the name of a RecoveryPolicy.
+func (p RecoveryPolicy) String() string {
+	for name, policy := range recoveryPolicies {
+		if policy == p {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// recoverSamples will check the db for samples left
+// queued or running by the last service run and handle
+// them according to the recovery policy. Errored samples
+// are also re-queued if requested.
+func (a *Archer) recoverSamples() error {
+
+	// collect the samples that need recovering
+	var requeue []*api.SampleInfo
+	for _, id := range a.getSampleIDs() {
+		sample, err := a.getSample(id)
+		if err != nil {
+			return err
+		}
+		switch sample.GetState() {
+		case api.State_UNKNOWN, api.State_RUNNING:
+			switch a.recoveryPolicy {
+			case RecoverResume:
+				resetSample(sample, false)
+			case RecoverRestart:
+				resetSample(sample, true)
+			case RecoverError:
+				previousState := sample.GetState()
+				checkError(sample, fmt.Errorf("processing was interrupted by a service restart"))
+				if err := a.finishSample(sample, previousState); err != nil {
+					return err
+				}
+				continue
+			}
+		case api.State_ERROR:
+			if !a.retryErrors {
+				continue
+			}
+			resetSample(sample, true)
+		default:
+			continue
+		}
+		requeue = append(requeue, sample)
+	}
+	if len(requeue) == 0 {
+		return nil
+	}
+	log.Infof("re-queuing %d unfinished samples (recovery policy: %v, retry errors: %v)", len(requeue), a.recoveryPolicy, a.retryErrors)
+
+	// register the samples and write them back to the db, taking a copy of
+	// each for its queued event as a registered sample can be cancelled
+	queued := make([]*api.SampleInfo, len(requeue))
+	for i, sample := range requeue {
+		if err := a.addSample(sample); err != nil {
+			return err
+		}
+		queued[i] = proto.Clone(sample).(*api.SampleInfo)
+		ctx, cancel := context.WithCancel(context.Background())
+		a.Lock()
+		a.inFlight[sample.GetSampleID()] = &inFlightSample{
+			sample: sample,
+			ctx:    ctx,
+			cancel: cancel,
+		}
+		a.Unlock()
+	}
+
+	// add the samples to the processing queue without holding up the service start,
+	// stopping if the service shuts down (any samples left are recovered on the next launch)
+	a.queueing.Add(1)
+	go func() {
+		defer a.queueing.Done()
+		for i, sample := range requeue {
+			a.publishEvent(api.EventType_QUEUED, queued[i], api.State_UNKNOWN, nil)
+			select {
+			case a.processChan <- sample:
+			case <-a.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// resetSample will clear the processing output from a
+// sample so that it can be processed again. If restart
+// is set, the start time and errors are also reset.
+func resetSample(sample *api.SampleInfo, restart bool) {
+	sample.State = api.State_UNKNOWN
+	sample.ProcessStats = nil
+	sample.Endpoint = ""
+	sample.EndTime = nil
+	if restart {
+		sample.Errors = []string{}
+		sample.Retries = 0
+		sample.StartTime = ptypes.TimestampNow()
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// TestRecoverSamples will check that samples left unfinished
+// by a previous service run are handled by the recovery policy.
+func TestRecoverSamples(t *testing.T) {
+	recoverDb := "./tmp-recover"
+	defer os.RemoveAll(recoverDb)
+
+	// set up a db with an interrupted sample and a finished sample
+	aInterface, shutdown, err := NewArcher(SetDb(recoverDb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := aInterface.(*Archer)
+	for id, state := range map[string]api.State{"running": api.State_RUNNING, "done": api.State_SUCCESS} {
+		sample, err := NewSample(SetID(id), SetRequest(&api.ProcessRequest{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sample.State = state
+		if err := a.addSample(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	// restart with the error policy
+	if _, err := ParseRecoveryPolicy("missing"); err == nil {
+		t.Fatal("unknown recovery policy was accepted")
+	}
+	aInterface, shutdown, err = NewArcher(SetDb(recoverDb), SetRecoveryPolicy("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a = aInterface.(*Archer)
+	interrupted, err := a.getSample("running")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interrupted.GetState() != api.State_ERROR || len(interrupted.GetErrors()) != 1 {
+		t.Fatalf("interrupted sample not marked as errored: %v", interrupted)
+	}
+	finished, err := a.getSample("done")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finished.GetState() != api.State_SUCCESS {
+		t.Fatal("finished sample was changed by recovery")
+	}
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	// restart and retry the errored sample, which will fail again as there is no manifest
+	aInterface, shutdown, err = NewArcher(SetDb(recoverDb), SetRetryErrors(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a = aInterface.(*Archer)
+	for i := 0; i < 50; i++ {
+		retried, err := a.getSample("running")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if retried.GetState() == api.State_ERROR && len(retried.GetErrors()) == 1 && strings.Contains(retried.GetErrors()[0], "manifest") {
+			break
+		}
+		if i == 49 {
+			t.Fatalf("errored sample was not retried: %v", retried)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRecoverShutdown will check that the service can be
+// shut down whilst recovered samples are still being queued.
+func TestRecoverShutdown(t *testing.T) {
+	recoverDb := "./tmp-recover-shutdown"
+	defer os.RemoveAll(recoverDb)
+
+	// leave several samples stranded in the db
+	aInterface, shutdown, err := NewArcher(SetDb(recoverDb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := aInterface.(*Archer)
+	for i := 0; i < 10; i++ {
+		sample, err := NewSample(SetID(fmt.Sprintf("stranded-%d", i)), SetRequest(&api.ProcessRequest{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sample.State = api.State_RUNNING
+		if err := a.addSample(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	// relaunch and shut down straight away, before the samples are all queued
+	_, shutdown, err = NewArcher(SetDb(recoverDb), SetNumWorkers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestProcessShutdown will check that a process request
+// waiting for a free worker fails cleanly if the service
+// shuts down.
+func TestProcessShutdown(t *testing.T) {
+	shutdownDb := "./tmp-process-shutdown"
+	defer os.RemoveAll(shutdownDb)
+	a, shutdown := newTestArcher(t, shutdownDb, SetNumWorkers(1))
+	storage := &blockingStorage{Storage: a.storage, started: make(chan struct{})}
+	a.storage = storage
+
+	// keep the worker busy
+	ref := getTestReference(t)
+	fastq := filepath.Join(shutdownDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[30:424], 'I'}})
+	request := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "running",
+		InputFASTQfiles: []string{fastq},
+		Scheme:          "test",
+		SchemeVersion:   1,
+	}
+	if _, err := a.Process(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	<-storage.started
+	a.RLock()
+	running := a.inFlight["running"]
+	a.RUnlock()
+
+	// shut down whilst the next request is waiting for the worker
+	waiting := &api.ProcessRequest{
+		ApiVersion:      apiVersion,
+		SampleID:        "waiting",
+		InputFASTQfiles: []string{fastq},
+		Scheme:          "test",
+		SchemeVersion:   1,
+	}
+	processErr := make(chan error)
+	go func() {
+		_, err := a.Process(context.Background(), waiting)
+		processErr <- err
+	}()
+	for {
+		a.RLock()
+		_, ok := a.inFlight["waiting"]
+		a.RUnlock()
+		if ok {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-processErr; status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable for a request during shutdown, got %v", err)
+	}
+	running.cancel()
+
+	// the waiting sample should have been marked as errored
+	aInterface, shutdown2, err := NewArcher(SetDb(shutdownDb), SetRecoveryPolicy("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown2()
+	sample, err := aInterface.(*Archer).getSample("waiting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sample.GetState() != api.State_ERROR || len(sample.GetErrors()) != 1 || !strings.Contains(sample.GetErrors()[0], "shut down") {
+		t.Fatalf("waiting sample was not errored: %v %v", sample.GetState(), sample.GetErrors())
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+	"github.com/will-rowe/archer/pkg/bucket"
+)
+
+// DefaultMaxRetries is the number of times a sample is retried after a transient upload error
+const DefaultMaxRetries = 5
+
+// backoffBase is the delay before the first retry
+const backoffBase = 2 * time.Second
+
+// backoffMax is the maximum delay between retries
+const backoffMax = 2 * time.Minute
+
+// getBackoff returns the delay to wait before a retry.
+// The delay doubles for each attempt, up to backoffMax,
+// and jitter (of up to half the delay) is applied so that workers retrying
+// at the same time don't hit the endpoint together.
+func getBackoff(attempt int) time.Duration {
+	delay := backoffMax
+	if attempt < 16 {
+		if d := backoffBase << uint(attempt); d < backoffMax {
+			delay = d
+		}
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryUpload will call an upload function for a sample,
+// retrying with a backoff if it hits a transient error
+// until it succeeds, the sample is cancelled or the
+// retries run out. Any errors added to the sample by a
+// failed attempt are cleared before the next one.
+func (a *Archer) retryUpload(ctx context.Context, sample *api.SampleInfo, upload func() (string, error)) (string, error) {
+	numErrors := len(sample.GetErrors())
+	for attempt := 0; ; attempt++ {
+		endpoint, err := upload()
+		if err == nil || ctx.Err() != nil || !bucket.IsRetryable(err) || attempt == a.maxRetries {
+			return endpoint, err
+		}
+		sample.Errors = sample.Errors[:numErrors]
+		sample.Retries++
+		delay := getBackoff(attempt)
+		log.Warnf("upload failed for %v, retrying in %v (retry %d of %d): %v", sample.GetSampleID(), delay, attempt+1, a.maxRetries, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+	"github.com/will-rowe/archer/pkg/bucket"
+)
+
+// flakyStorage wraps a Storage so that the first uploads
+// fail with a transient S3 server error.
+type flakyStorage struct {
+	bucket.Storage
+	failures int32
+	uploads  int32
+	onFail   func()
+}
+
+// Upload implements the Storage interface.
+func (f *flakyStorage) Upload(ctx context.Context, reader io.Reader, key string) (string, error) {
+	if atomic.AddInt32(&f.uploads, 1) <= f.failures {
+		if f.onFail != nil {
+			f.onFail()
+		}
+		return "", awserr.NewRequestFailure(awserr.New("InternalError", "we encountered an internal error", nil), 500, "test")
+	}
+	return f.Storage.Upload(ctx, reader, key)
+}
+
+// TestGetBackoff will check the retry delays grow and
+// stay within bounds.
+func TestGetBackoff(t *testing.T) {
+	for attempt := 0; attempt < 100; attempt++ {
+		delay := getBackoff(attempt)
+		max := backoffMax
+		if attempt < 6 {
+			max = backoffBase << uint(attempt)
+		}
+		if delay < max/2 || delay > max {
+			t.Fatalf("backoff out of bounds for attempt %d: got %v, wanted between %v and %v", attempt, delay, max/2, max)
+		}
+	}
+}
+
+// TestProcessRetry will check that a sample is processed
+// again after a transient upload error, and is errored
+// once the retries run out.
+func TestProcessRetry(t *testing.T) {
+	retryDb := "./tmp-retry"
+	defer os.RemoveAll(retryDb)
+	if err := os.MkdirAll(retryDb, 0755); err != nil {
+		t.Fatal(err)
+	}
+	ref := getTestReference(t)
+	fastq := filepath.Join(retryDb, "reads.fastq")
+	writeTestFASTQ(t, fastq, []testRead{{ref[30:424], 'I'}, {ref[350:774], 'I'}})
+	tests := []struct {
+		maxRetries int
+		failures   int32
+		state      api.State
+		retries    int32
+		uploads    int32
+	}{
+		{1, 0, api.State_SUCCESS, 0, 1},
+		{1, 1, api.State_SUCCESS, 1, 2},
+		{1, 2, api.State_ERROR, 1, 2},
+		{0, 1, api.State_ERROR, 0, 1},
+	}
+	for i, test := range tests {
+		db := filepath.Join(retryDb, fmt.Sprintf("test-%d", i))
+		a, shutdown := newTestArcher(t, db, SetMaxRetries(test.maxRetries))
+		storage := &flakyStorage{Storage: a.storage, failures: test.failures}
+		a.storage = storage
+		request := &api.ProcessRequest{
+			ApiVersion:      apiVersion,
+			SampleID:        "retry",
+			InputFASTQfiles: []string{fastq},
+			Scheme:          "test",
+			SchemeVersion:   1,
+		}
+		if _, err := a.Process(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		sample := waitForSample(t, a, "retry")
+		shutdown()
+		if sample.GetState() != test.state || sample.GetRetries() != test.retries || atomic.LoadInt32(&storage.uploads) != test.uploads {
+			t.Fatalf("test %d: wanted %v after %d retries and %d uploads, got %v after %d retries and %d uploads (%v)", i, test.state, test.retries, test.uploads, sample.GetState(), sample.GetRetries(), storage.uploads, sample.GetErrors())
+		}
+
+		// the reads should be uploaded once, and an errored sample should only record the final upload error
+		switch test.state {
+		case api.State_SUCCESS:
+			if stats := sample.GetProcessStats(); stats.GetTotalReads() != 2 || stats.GetKeptReads() != 2 || len(sample.GetErrors()) != 0 {
+				t.Fatalf("test %d: wanted 2 of 2 reads kept, got %d of %d (%v)", i, stats.GetKeptReads(), stats.GetTotalReads(), sample.GetErrors())
+			}
+			if reads := readUpload(t, db, "retry"); len(reads) != 2 {
+				t.Fatalf("test %d: wanted 2 uploaded reads, got %d", i, len(reads))
+			}
+		case api.State_ERROR:
+			if len(sample.GetErrors()) != 1 || len(sample.GetEndpoint()) != 0 {
+				t.Fatalf("test %d: wanted a single upload error, got %v", i, sample.GetErrors())
+			}
+		}
+	}
+}
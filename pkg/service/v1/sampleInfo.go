@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/golang/protobuf/ptypes"
 
@@ -77,6 +78,12 @@ func GetAmpliconCoverage(sampleStats *api.SampleStats) (int, int, float64) {
 	return coveredAmplicons, totalAmplicons, float64(meanCoverage) / float64(totalAmplicons)
 }
 
+// sampleFileName returns a sample ID that is safe
+// to use in a local file name.
+func sampleFileName(sampleID string) string {
+	return strings.NewReplacer("/", "_", "\\", "_").Replace(sampleID)
+}
+
 // checkError will check an error, add it to the sample
 // and update its state. True is returned if an error
 // was received, False if error was nil.
@@ -1,66 +1,70 @@
 package service
 
 import (
-	"github.com/golang/protobuf/proto"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	api "github.com/will-rowe/archer/pkg/api/v1"
 )
 
-// Watch will respond to WatchRequests by streaming completed samples
-// back to the user as they are marked done by Archer.
+// Watch will respond to WatchRequests by streaming sample events
+// back to the user as samples are queued, change state or make
+// progress. The first response is a snapshot of the queued and
+// running samples, which can also include finished samples.
 func (a *Archer) Watch(request *api.WatchRequest, stream api.Archer_WatchServer) error {
 	log.Info("watch request received")
 
-	// set up a chan for the watcher to receive updates on
-	if a.watcherChan == nil {
-		a.watcherChan = make(chan *api.SampleInfo)
-	}
+	// subscribe for updates before checking the db so that nothing is missed, with extra room
+	// in the buffer (one update per sample) so the watcher isn't dropped whilst a large snapshot is sent
+	sub := a.watchers.subscribe(watcherBufferSize + a.db.Len())
+	defer a.watchers.unsubscribe(sub)
 
-	// start with a loop over the db and send all finished samples (if requested)
-	if request.GetSendFinished() {
-		response := &api.WatchResponse{
-			ApiVersion: a.version,
-			Samples:    []*api.SampleInfo{},
+	// start with a loop over the db and send all queued and running samples, plus finished samples (if requested)
+	response := &api.WatchResponse{
+		ApiVersion: a.version,
+		Samples:    []*api.SampleInfo{},
+		EventType:  api.EventType_SNAPSHOT,
+	}
+	for _, id := range a.getSampleIDs() {
+		sample, err := a.getSample(id)
+		if err != nil {
+			return err
 		}
-		for key := range a.db.Keys() {
-			sample := &api.SampleInfo{}
-			data, err := a.db.Get(key)
-			if err != nil {
-				return err
-			}
-			if err := proto.Unmarshal(data, sample); err != nil {
-				return err
-			}
-			if sample.GetState() == api.State_SUCCESS {
+		switch sample.GetState() {
+		case api.State_UNKNOWN, api.State_RUNNING:
+			response.Samples = append(response.Samples, sample)
+		case api.State_SUCCESS, api.State_FAILED_QC:
+			if request.GetSendFinished() {
 				response.Samples = append(response.Samples, sample)
 			}
 		}
-		if err := stream.Send(response); err != nil {
-			log.Errorf("watch request failed to return a message: %v", err)
-			return err
-		}
+	}
+	if err := stream.Send(response); err != nil {
+		log.Errorf("watch request failed to return a message: %v", err)
+		return err
 	}
 
-	// collect completed samples as they are finished by the Process workers
-	errChan := make(chan error)
-	go func() {
-		for sample := range a.watcherChan {
-			log.Infof("watcher received finished sample - %s", sample.GetSampleID())
-			resp := &api.WatchResponse{ApiVersion: a.version, Samples: []*api.SampleInfo{sample}}
-			if err := stream.Send(resp); err != nil {
-				errChan <- err
-			}
-		}
-	}()
-
-	// wait for a shutdown or error
+	// send sample events as they are published by Archer, until the stream or service ends
 	for {
 		select {
-		case err := <-errChan:
-			return err
+		case event, ok := <-sub.updates:
+			if !ok {
+				if sub.dropped {
+					return status.Errorf(codes.ResourceExhausted, "watcher disconnected for not keeping up with updates")
+				}
+				log.Info("closing watcher stream as service is shutting down")
+				return nil
+			}
+			if event.GetEventType() == api.EventType_PROGRESS && !request.GetSendProgress() {
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				log.Errorf("watch request failed to return a message: %v", err)
+				return err
+			}
 		case <-stream.Context().Done():
 			log.Info("closing watcher stream")
-			close(errChan)
 			return nil
 		}
 	}
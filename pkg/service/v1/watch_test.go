@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// testWatchServer is an Archer_WatchServer that passes
+// the responses back to the test. The first send can be
+// held up to act like a slow client.
+type testWatchServer struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses chan *api.WatchResponse
+	hold      chan struct{}
+}
+
+// Send implements the Archer_WatchServer interface.
+func (s *testWatchServer) Send(response *api.WatchResponse) error {
+	if s.hold != nil {
+		<-s.hold
+		s.hold = nil
+	}
+	s.responses <- response
+	return nil
+}
+
+// Context implements the grpc.ServerStream interface.
+func (s *testWatchServer) Context() context.Context {
+	return s.ctx
+}
+
+// nextResponse will wait for the next watch response.
+func (s *testWatchServer) nextResponse(t *testing.T) *api.WatchResponse {
+	select {
+	case response := <-s.responses:
+		return response
+	case <-time.After(5 * time.Second):
+		t.Fatal("no watch response received")
+		return nil
+	}
+}
+
+// TestArcher_Watch will check that a watcher gets a
+// snapshot followed by live events, and that it is
+// unsubscribed when the client disconnects.
+func TestArcher_Watch(t *testing.T) {
+	watchDb := "./tmp-watch"
+	defer os.RemoveAll(watchDb)
+	a, shutdown := newTestArcher(t, watchDb)
+	defer shutdown()
+
+	// add a finished sample and enough queued samples to need more than the default buffer
+	numQueued := watcherBufferSize * 2
+	for i := 0; i <= numQueued; i++ {
+		sample, err := NewSample(SetID(fmt.Sprintf("sample-%d", i)), SetRequest(&api.ProcessRequest{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == numQueued {
+			sample.State = api.State_SUCCESS
+		}
+		if err := a.addSample(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// start a slow watcher
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &testWatchServer{ctx: ctx, responses: make(chan *api.WatchResponse, numQueued*2), hold: make(chan struct{})}
+	watchErr := make(chan error)
+	go func() {
+		watchErr <- a.Watch(&api.WatchRequest{ApiVersion: apiVersion, SendFinished: true}, stream)
+	}()
+	for a.watchers.numSubscribers() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// publish more events than the default buffer whilst the snapshot is held up
+	for i := 0; i < numQueued; i++ {
+		a.publishEvent(api.EventType_STATE_CHANGE, &api.SampleInfo{SampleID: fmt.Sprintf("sample-%d", i), State: api.State_RUNNING}, api.State_UNKNOWN, nil)
+	}
+	close(stream.hold)
+	snapshot := stream.nextResponse(t)
+	if snapshot.GetEventType() != api.EventType_SNAPSHOT || len(snapshot.GetSamples()) != numQueued+1 {
+		t.Fatalf("wanted a snapshot of %d samples, got %v with %d samples", numQueued+1, snapshot.GetEventType(), len(snapshot.GetSamples()))
+	}
+	for i := 0; i < numQueued; i++ {
+		if event := stream.nextResponse(t); event.GetEventType() != api.EventType_STATE_CHANGE || event.GetSamples()[0].GetState() != api.State_RUNNING {
+			t.Fatalf("unexpected event: %v", event)
+		}
+	}
+
+	// progress events are only sent if requested
+	a.publishEvent(api.EventType_PROGRESS, &api.SampleInfo{SampleID: "sample-0"}, api.State_RUNNING, &api.SampleProgress{})
+	a.publishEvent(api.EventType_STATE_CHANGE, &api.SampleInfo{SampleID: "sample-0", State: api.State_SUCCESS}, api.State_RUNNING, nil)
+	if event := stream.nextResponse(t); event.GetEventType() != api.EventType_STATE_CHANGE || event.GetPreviousState() != api.State_RUNNING {
+		t.Fatalf("unexpected event: %v", event)
+	}
+
+	// disconnect the client
+	cancel()
+	select {
+	case err := <-watchErr:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not return after the client disconnected")
+	}
+	if a.watchers.numSubscribers() != 0 {
+		t.Fatal("watcher was not unsubscribed")
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// watcherBufferSize is the number of updates that can be
+// queued for a watcher before it is considered too slow
+// and is disconnected (on top of any room needed for
+// the watcher's snapshot).
+const watcherBufferSize = 64
+
+// subscriber is a single connected watcher.
+type subscriber struct {
+	// updates receives a copy of each published event
+	updates chan *api.WatchResponse
+	// dropped is set if the subscriber was disconnected for falling behind
+	dropped bool
+}
+
+// watcherHub broadcasts sample events to all
+// connected watchers.
+//
+// Publishing never blocks; each subscriber has
+// its own buffered queue and a subscriber whose
+// queue fills up is disconnected so that it can't
+// hold up the process workers or other watchers.
+type watcherHub struct {
+	sync.Mutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+// newWatcherHub returns an initialised watcherHub.
+func newWatcherHub() *watcherHub {
+	return &watcherHub{
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// subscribe will register a new watcher with the hub,
+// which can queue up to bufferSize updates. The updates
+// channel of the returned subscriber is closed when the
+// watcher is unsubscribed, dropped or the hub is closed.
+func (h *watcherHub) subscribe(bufferSize int) *subscriber {
+	h.Lock()
+	defer h.Unlock()
+	sub := &subscriber{
+		updates: make(chan *api.WatchResponse, bufferSize),
+	}
+	if h.closed {
+		close(sub.updates)
+		return sub
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// unsubscribe will remove a watcher from the hub.
+// It is safe to call this more than once.
+func (h *watcherHub) unsubscribe(sub *subscriber) {
+	h.Lock()
+	defer h.Unlock()
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.updates)
+	}
+}
+
+// publish will send a copy of the event to
+// every subscriber.
+func (h *watcherHub) publish(event *api.WatchResponse) {
+	h.Lock()
+	defer h.Unlock()
+	for sub := range h.subscribers {
+		select {
+		case sub.updates <- proto.Clone(event).(*api.WatchResponse):
+		default:
+			log.Warn("dropping watcher that is not keeping up with updates")
+			sub.dropped = true
+			delete(h.subscribers, sub)
+			close(sub.updates)
+		}
+	}
+}
+
+// numSubscribers returns the number of connected
+// watchers.
+func (h *watcherHub) numSubscribers() int {
+	h.Lock()
+	defer h.Unlock()
+	return len(h.subscribers)
+}
+
+// close will disconnect all watchers and stop
+// any new ones from subscribing.
+func (h *watcherHub) close() {
+	h.Lock()
+	defer h.Unlock()
+	for sub := range h.subscribers {
+		delete(h.subscribers, sub)
+		close(sub.updates)
+	}
+	h.closed = true
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	api "github.com/will-rowe/archer/pkg/api/v1"
+)
+
+// TestWatcherHub will check that every subscriber receives
+// every update and that slow subscribers are dropped.
+func TestWatcherHub(t *testing.T) {
+	hub := newWatcherHub()
+	sub1 := hub.subscribe(watcherBufferSize)
+	sub2 := hub.subscribe(watcherBufferSize)
+
+	// both subscribers get the update
+	hub.publish(&api.WatchResponse{Samples: []*api.SampleInfo{{SampleID: "s1"}}})
+	for _, sub := range []*subscriber{sub1, sub2} {
+		if event := <-sub.updates; event.GetSamples()[0].GetSampleID() != "s1" {
+			t.Fatalf("subscriber received wrong sample: %v", event.GetSamples()[0].GetSampleID())
+		}
+	}
+
+	// fill the second subscriber's queue and check it is dropped
+	for i := 0; i <= watcherBufferSize; i++ {
+		hub.publish(&api.WatchResponse{Samples: []*api.SampleInfo{{SampleID: "s2"}}})
+		<-sub1.updates
+	}
+	if hub.numSubscribers() != 1 || !sub2.dropped {
+		t.Fatal("slow subscriber was not dropped")
+	}
+
+	// unsubscribe and check for a closed channel
+	hub.unsubscribe(sub1)
+	hub.unsubscribe(sub1)
+	if _, ok := <-sub1.updates; ok {
+		t.Fatal("unsubscribed watcher still open")
+	}
+	hub.close()
+	if _, ok := <-hub.subscribe(watcherBufferSize).updates; ok {
+		t.Fatal("subscribed to a closed hub")
+	}
+}